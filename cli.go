@@ -0,0 +1,52 @@
+package main
+
+import (
+	"RESTAPI/config"
+	"RESTAPI/usecase"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// storageGCGrace ไม่ลบไฟล์ที่เพิ่งสร้าง เพราะไฟล์ถูกบันทึกก่อนแถวในฐานข้อมูล
+const storageGCGrace = time.Hour
+
+// runCommand รันคำสั่งจาก command line แทนการเปิด server คืน exit code
+func runCommand(cfg *config.Config, maintenance usecase.MaintenanceUsecase, args []string) int {
+	switch args[0] {
+	case "storage-gc":
+		fs := flag.NewFlagSet("storage-gc", flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "report orphaned and missing files without changing anything")
+		grace := fs.Duration("grace", storageGCGrace, "keep unreferenced files newer than this")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		report, err := maintenance.ReconcileStorage(*dryRun, *grace)
+		if report != nil {
+			out, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(out))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "storage-gc: %v\n", err)
+			return 1
+		}
+		return 0
+	case "purge-versions":
+		fs := flag.NewFlagSet("purge-versions", flag.ContinueOnError)
+		retention := fs.Duration("retention", cfg.Upload.EvidenceRetention, "remove versions superseded longer ago than this")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		purged, err := maintenance.PurgeSupersededEvidence(*retention)
+		fmt.Printf("purged %d superseded evidence files\n", purged)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "purge-versions: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q (available: storage-gc, purge-versions)\n", args[0])
+	return 2
+}
@@ -5,6 +5,7 @@ import (
     "log"
     "os"
     "strconv"
+    "time"
 
     "github.com/joho/godotenv"
 )
@@ -14,8 +15,50 @@ type Config struct {
     DSN        string // Data Source Name สำหรับการเชื่อมต่อฐานข้อมูล
     JWTSecret  string // Secret key สำหรับ JWT
     ServerPort int    // พอร์ตของเซิร์ฟเวอร์
+    FileURLTTL time.Duration // อายุของลิงก์ดาวน์โหลดไฟล์แบบ signed URL
+    Storage    Storage
+    Upload     Upload
+    PDFTemplateDir string // โฟลเดอร์แม่แบบเอกสาร PDF ที่ใช้แทนแม่แบบเริ่มต้น (ไม่บังคับ)
+    PublicBaseURL  string // ที่อยู่สาธารณะของระบบ ใช้สร้างลิงก์ตรวจสอบเอกสารใน QR code
+    RequiredHoursPerYear uint // ชั่วโมงกิจกรรมขั้นต่ำต่อปีการศึกษา 0 คือไม่กำหนด
+    AnalyticsCacheTTL time.Duration // อายุของผลสถิติที่เก็บไว้ 0 คือคำนวณใหม่ทุกครั้ง
+    ReviewOverdueDays uint // จำนวนวันหลังส่งหลักฐานที่ถือว่ารอตรวจเกินกำหนด
+    FeedbackEditWindow time.Duration // ระยะเวลาหลังส่งความคิดเห็นครั้งแรกที่นักศึกษายังแก้ไขได้
+    Mail        Mail
     Admin       Admin
 }
+
+// Mail ค่าคอนฟิก SMTP สำหรับส่งอีเมล ถ้า SMTPHost ว่างจะไม่ส่งอีเมล
+type Mail struct {
+    SMTPHost     string
+    SMTPPort     int
+    SMTPUser     string
+    SMTPPassword string
+    From         string
+}
+
+// Upload ข้อจำกัดของไฟล์หลักฐานที่อัปโหลด
+type Upload struct {
+    MaxSize    int64  // ขนาดสูงสุด (ไบต์)
+    MaxPages   int    // จำนวนหน้าสูงสุดของ PDF
+    MaxAttachments int // จำนวนไฟล์แนบสูงสุดต่อการเข้าร่วมหนึ่งครั้ง
+    EvidenceRetention time.Duration // ระยะเวลาเก็บไฟล์หลักฐานเวอร์ชันเก่าก่อนลบ
+    ClamdAddr  string // ที่อยู่ clamd สำหรับตรวจมัลแวร์ ถ้าว่างจะไม่ตรวจ
+}
+
+// Storage ค่าคอนฟิกของที่เก็บไฟล์ (local หรือ s3)
+type Storage struct {
+    Driver      string
+    LocalDir    string
+    S3Endpoint  string
+    S3AccessKey string
+    S3SecretKey string
+    S3Bucket    string
+    S3Region    string
+    S3UseSSL    bool
+    GCInterval  time.Duration // รอบการตรวจไฟล์กำพร้า 0 คือปิด
+    GCRepair    bool          // ให้รอบตรวจอัตโนมัติลบไฟล์และล้างการอ้างอิงจริง ค่าเริ่มต้นรายงานอย่างเดียว
+}
 type Admin struct{
     Email string
     Password string
@@ -51,6 +94,144 @@ func LoadConfig() *Config {
         log.Fatalf("Invalid SERVER_PORT value")
     }
 
+    // ดึงค่า FILE_URL_TTL (นาที) ถ้าไม่กำหนดใช้ 10 นาที
+    fileURLTTL := 10 * time.Minute
+    if v := os.Getenv("FILE_URL_TTL"); v != "" {
+        minutes, err := strconv.Atoi(v)
+        if err != nil || minutes <= 0 {
+            log.Fatalf("Invalid FILE_URL_TTL value")
+        }
+        fileURLTTL = time.Duration(minutes) * time.Minute
+    }
+
+    // ดึงค่าที่เก็บไฟล์ ค่าเริ่มต้นเก็บบนดิสก์ที่ ./uploads
+    storage := Storage{
+        Driver:      os.Getenv("STORAGE_DRIVER"),
+        LocalDir:    os.Getenv("STORAGE_LOCAL_DIR"),
+        S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+        S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+        S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+        S3Bucket:    os.Getenv("S3_BUCKET"),
+        S3Region:    os.Getenv("S3_REGION"),
+        S3UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+    }
+    if storage.LocalDir == "" {
+        storage.LocalDir = "./uploads"
+    }
+    storage.GCInterval = 24 * time.Hour
+    if v := os.Getenv("STORAGE_GC_INTERVAL_HOURS"); v != "" {
+        hours, err := strconv.Atoi(v)
+        if err != nil || hours < 0 {
+            log.Fatalf("Invalid STORAGE_GC_INTERVAL_HOURS value")
+        }
+        storage.GCInterval = time.Duration(hours) * time.Hour
+    }
+    storage.GCRepair = os.Getenv("STORAGE_GC_REPAIR") == "true"
+
+    // ข้อจำกัดไฟล์อัปโหลด ค่าเริ่มต้น 10 MB และ 20 หน้า
+    upload := Upload{
+        MaxSize:   10 << 20,
+        MaxPages:  20,
+        MaxAttachments: 10,
+        EvidenceRetention: 30 * 24 * time.Hour,
+        ClamdAddr: os.Getenv("CLAMD_ADDR"),
+    }
+    if v := os.Getenv("UPLOAD_MAX_SIZE_MB"); v != "" {
+        mb, err := strconv.Atoi(v)
+        if err != nil || mb <= 0 {
+            log.Fatalf("Invalid UPLOAD_MAX_SIZE_MB value")
+        }
+        upload.MaxSize = int64(mb) << 20
+    }
+    if v := os.Getenv("UPLOAD_MAX_PAGES"); v != "" {
+        pages, err := strconv.Atoi(v)
+        if err != nil || pages <= 0 {
+            log.Fatalf("Invalid UPLOAD_MAX_PAGES value")
+        }
+        upload.MaxPages = pages
+    }
+    if v := os.Getenv("UPLOAD_MAX_ATTACHMENTS"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n <= 0 {
+            log.Fatalf("Invalid UPLOAD_MAX_ATTACHMENTS value")
+        }
+        upload.MaxAttachments = n
+    }
+    if v := os.Getenv("EVIDENCE_RETENTION_DAYS"); v != "" {
+        days, err := strconv.Atoi(v)
+        if err != nil || days < 0 {
+            log.Fatalf("Invalid EVIDENCE_RETENTION_DAYS value")
+        }
+        upload.EvidenceRetention = time.Duration(days) * 24 * time.Hour
+    }
+
+    // ที่อยู่สาธารณะสำหรับลิงก์ตรวจสอบเอกสาร ถ้าไม่กำหนดใช้ localhost ตามพอร์ตของเซิร์ฟเวอร์
+    // ซึ่งใช้ได้เฉพาะตอนพัฒนา QR code ในเอกสารที่ออกจะสแกนจากเครื่องอื่นไม่ได้
+    publicBaseURL := os.Getenv("PUBLIC_BASE_URL")
+    if publicBaseURL == "" {
+        publicBaseURL = fmt.Sprintf("http://localhost:%d", serverPort)
+        log.Printf("WARNING: PUBLIC_BASE_URL is not set, verification links in issued documents will point to %s", publicBaseURL)
+    }
+
+    // ชั่วโมงกิจกรรมขั้นต่ำต่อปีการศึกษาสำหรับนักศึกษาที่ไม่ตรงกับเกณฑ์ชั่วโมงใด
+    var requiredHours uint
+    if v := os.Getenv("REQUIRED_HOURS_PER_YEAR"); v != "" {
+        hours, err := strconv.Atoi(v)
+        if err != nil || hours < 0 {
+            log.Fatalf("Invalid REQUIRED_HOURS_PER_YEAR value")
+        }
+        requiredHours = uint(hours)
+    }
+
+    // อายุของผลสถิติสำหรับผู้ดูแลระบบ (วินาที) ถ้าไม่กำหนดใช้ 5 นาที
+    analyticsCacheTTL := 5 * time.Minute
+    if v := os.Getenv("ANALYTICS_CACHE_TTL_SECONDS"); v != "" {
+        seconds, err := strconv.Atoi(v)
+        if err != nil || seconds < 0 {
+            log.Fatalf("Invalid ANALYTICS_CACHE_TTL_SECONDS value")
+        }
+        analyticsCacheTTL = time.Duration(seconds) * time.Second
+    }
+
+    // จำนวนวันที่หลักฐานรอตรวจได้ก่อนแสดงว่าเกินกำหนด ถ้าไม่กำหนดใช้ 7 วัน
+    var reviewOverdueDays uint = 7
+    if v := os.Getenv("REVIEW_OVERDUE_DAYS"); v != "" {
+        days, err := strconv.Atoi(v)
+        if err != nil || days < 0 {
+            log.Fatalf("Invalid REVIEW_OVERDUE_DAYS value")
+        }
+        reviewOverdueDays = uint(days)
+    }
+
+    // ระยะเวลาที่แก้ไขความคิดเห็นต่อกิจกรรมได้ (ชั่วโมง) ถ้าไม่กำหนดใช้ 72 ชั่วโมง
+    feedbackEditWindow := 72 * time.Hour
+    if v := os.Getenv("FEEDBACK_EDIT_WINDOW_HOURS"); v != "" {
+        hours, err := strconv.Atoi(v)
+        if err != nil || hours < 0 {
+            log.Fatalf("Invalid FEEDBACK_EDIT_WINDOW_HOURS value")
+        }
+        feedbackEditWindow = time.Duration(hours) * time.Hour
+    }
+
+    // SMTP สำหรับส่งอีเมล พอร์ตเริ่มต้น 587 ผู้ส่งเริ่มต้นคือ SMTP_USER
+    mail := Mail{
+        SMTPHost:     os.Getenv("SMTP_HOST"),
+        SMTPPort:     587,
+        SMTPUser:     os.Getenv("SMTP_USER"),
+        SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+        From:         os.Getenv("SMTP_FROM"),
+    }
+    if v := os.Getenv("SMTP_PORT"); v != "" {
+        port, err := strconv.Atoi(v)
+        if err != nil || port <= 0 {
+            log.Fatalf("Invalid SMTP_PORT value")
+        }
+        mail.SMTPPort = port
+    }
+    if mail.From == "" {
+        mail.From = mail.SMTPUser
+    }
+
     // ตรวจสอบว่าค่าที่จำเป็นถูกตั้งค่าแล้ว
     if dsn == "" || jwtSecret == "" {
         log.Fatalf("Required environment variables are missing")
@@ -61,6 +242,16 @@ func LoadConfig() *Config {
         DSN:        dsn,
         JWTSecret:  jwtSecret,
         ServerPort: serverPort,
+        FileURLTTL: fileURLTTL,
+        Storage:    storage,
+        Upload:     upload,
+        PDFTemplateDir: os.Getenv("PDF_TEMPLATE_DIR"),
+        PublicBaseURL:  publicBaseURL,
+        RequiredHoursPerYear: requiredHours,
+        AnalyticsCacheTTL: analyticsCacheTTL,
+        ReviewOverdueDays: reviewOverdueDays,
+        FeedbackEditWindow: feedbackEditWindow,
+        Mail:       mail,
         Admin: Admin{
             Email: email,
             Password: password,
@@ -0,0 +1,77 @@
+package entities
+
+import "time"
+
+// ParticipationStat จำนวนการเข้าร่วมและชั่วโมงของกลุ่มหนึ่ง (คณะ สาขา ชั้นปี หรือปีการศึกษา)
+// ApprovedHours นับกิจกรรมภายในที่รับรองแล้วรวมกับกิจกรรมภายนอก
+type ParticipationStat struct {
+	GroupID        uint   `json:"group_id"`
+	GroupName      string `json:"group_name"`
+	Participations uint   `json:"participations"`
+	Inside         uint   `json:"inside"`
+	Outside        uint   `json:"outside"`
+	Students       uint   `json:"students"`
+	ApprovedHours  uint   `json:"approved_hours"`
+	PendingHours   uint   `json:"pending_hours"`
+}
+
+// EventFillRate อัตราการเข้าร่วมของกิจกรรม Capacity คือที่นั่งว่างรวมกับผู้ที่เข้าร่วมแล้ว
+type EventFillRate struct {
+	EventID   uint      `json:"event_id"`
+	EventName string    `json:"event_name"`
+	StartDate time.Time `json:"start_date"`
+	Capacity  uint      `json:"capacity"`
+	Joined    uint      `json:"joined"`
+	Approved  uint      `json:"approved"`
+	FillRate  float64   `json:"fill_rate"`
+}
+
+// OrganizerStat สรุปกิจกรรมที่อาจารย์สร้างหรือร่วมจัด
+type OrganizerStat struct {
+	UserID       uint   `json:"user_id"`
+	Name         string `json:"name"`
+	Created      uint   `json:"created"`
+	CoOrganized  uint   `json:"co_organized"`
+	Participants uint   `json:"participants"`
+	Approved     uint   `json:"approved"`
+}
+
+// TurnaroundStat ระยะเวลาตั้งแต่ส่งหลักฐานครั้งล่าสุดจนได้รับการรับรอง (ชั่วโมง)
+type TurnaroundStat struct {
+	GroupID      uint    `json:"group_id"`
+	GroupName    string  `json:"group_name"`
+	Certified    uint    `json:"certified"`
+	AverageHours float64 `json:"average_hours"`
+	MinHours     float64 `json:"min_hours"`
+	MaxHours     float64 `json:"max_hours"`
+}
+
+// TurnaroundReport ระยะเวลาการรับรองรวมทุกคณะและแยกตามคณะของนักศึกษา
+type TurnaroundReport struct {
+	Overall   TurnaroundStat   `json:"overall"`
+	Faculties []TurnaroundStat `json:"faculties"`
+}
+
+// RequirementStat สัดส่วนนักศึกษาที่ผ่านเกณฑ์ของตัวเองในปีการศึกษา แยกตามคณะ
+type RequirementStat struct {
+	GroupID   uint    `json:"group_id"`
+	GroupName string  `json:"group_name"`
+	Students  uint    `json:"students"`
+	OnTrack   uint    `json:"on_track"`
+	Share     float64 `json:"share"`
+}
+
+// StudentScope คณะ สาขา และชั้นปีของนักศึกษาหนึ่งคน ใช้เลือกเกณฑ์ชั่วโมงของนักศึกษา
+type StudentScope struct {
+	UserID      uint
+	FacultyID   uint
+	FacultyName string
+	BranchID    uint
+	ClassYear   uint
+}
+
+// RequirementReport สัดส่วนนักศึกษาที่มีชั่วโมงครบ รวมทุกคณะและแยกตามคณะ
+type RequirementReport struct {
+	Overall   RequirementStat   `json:"overall"`
+	Faculties []RequirementStat `json:"faculties"`
+}
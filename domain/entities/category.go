@@ -0,0 +1,32 @@
+package entities
+
+// MaxSDG เป้าหมายการพัฒนาที่ยั่งยืนของสหประชาชาติมีหมายเลข 1 ถึง 17
+const MaxSDG = 17
+
+// Category หมวดหมู่ของกิจกรรมที่ผู้ดูแลระบบกำหนด เช่น สิ่งแวดล้อม ชุมชน ศาสนา งานบริการมหาวิทยาลัย
+// SDGs คือเป้าหมาย SDG ที่หมวดหมู่นี้สอดคล้อง ใช้เป็นค่าเริ่มต้นของกิจกรรมที่ไม่ได้ระบุ SDG เอง
+type Category struct {
+	CategoryID  uint   `gorm:"primaryKey;autoIncrement" json:"category_id"`
+	Name        string `gorm:"size:100;not null;unique" json:"name"`
+	Description string `json:"description"`
+	SDGs        string `gorm:"type:json" json:"-"`
+}
+
+type CategoryResponse struct {
+	CategoryID  uint   `json:"category_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SDGs        []uint `json:"sdgs"`
+}
+
+// CategoryStat จำนวนการเข้าร่วมและชั่วโมงของหมวดหมู่หนึ่ง CategoryID เป็น 0 คือไม่ระบุหมวดหมู่
+type CategoryStat struct {
+	CategoryID     uint   `json:"category_id"`
+	CategoryName   string `json:"category_name"`
+	Participations uint   `json:"participations"`
+	Inside         uint   `json:"inside"`
+	Outside        uint   `json:"outside"`
+	Students       uint   `json:"students"`
+	ApprovedHours  uint   `json:"approved_hours"`
+	PendingHours   uint   `json:"pending_hours"`
+}
@@ -0,0 +1,44 @@
+package entities
+
+// ผลของแต่ละรายการในการรับรองแบบกลุ่ม
+const (
+	CertifyApproved = "approved"
+	CertifyRejected = "rejected"
+	CertifyFailed   = "failed"
+)
+
+// BulkCertifyItem ผู้เข้าร่วมหนึ่งคน Status และ Comment ที่ไม่ระบุจะใช้ค่าร่วมของคำขอ
+type BulkCertifyItem struct {
+	UserID  uint    `json:"user_id"`
+	Status  *bool   `json:"status"`
+	Comment *string `json:"comment"`
+}
+
+// BulkCertifyRequest รับรองหรือตีกลับผู้เข้าร่วมตาม Items
+// หรือทุกคนที่ส่งหลักฐานแล้วแต่ยังไม่ได้รับการรับรองเมื่อ AllUploaded เป็น true
+// (ไม่รวมผู้ที่ถูกปฏิเสธแล้วและยังไม่ได้ส่งหลักฐานใหม่)
+type BulkCertifyRequest struct {
+	Status      bool              `json:"status"`
+	Comment     string            `json:"comment"`
+	AllUploaded bool              `json:"all_uploaded"`
+	Items       []BulkCertifyItem `json:"items"`
+}
+
+// BulkCertifyResult ผลของผู้เข้าร่วมหนึ่งคน Error มีค่าเมื่อ Result เป็น failed
+type BulkCertifyResult struct {
+	UserID  uint   `json:"user_id"`
+	Status  bool   `json:"status"`
+	Comment string `json:"comment"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkCertifyReport สรุปผลการรับรองแบบกลุ่มของกิจกรรมหนึ่ง
+type BulkCertifyReport struct {
+	EventID  uint                `json:"event_id"`
+	Total    uint                `json:"total"`
+	Approved uint                `json:"approved"`
+	Rejected uint                `json:"rejected"`
+	Failed   uint                `json:"failed"`
+	Rows     []BulkCertifyResult `json:"rows"`
+}
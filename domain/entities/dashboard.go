@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// DashboardVisit เวลาที่ผู้ใช้เปิดหน้าสรุปงานตรวจครั้งล่าสุด ใช้หาหลักฐานที่อัปโหลดใหม่
+type DashboardVisit struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	VisitedAt time.Time `gorm:"not null" json:"visited_at"`
+}
+
+// PendingReviewEvent จำนวนการเข้าร่วมที่ส่งหลักฐานแล้วแต่ยังไม่ได้ตรวจของกิจกรรมหนึ่ง
+type PendingReviewEvent struct {
+	EventID      uint      `json:"event_id"`
+	EventName    string    `json:"event_name"`
+	StartDate    time.Time `json:"start_date"`
+	Pending      uint      `json:"pending"`
+	OldestUpload time.Time `json:"oldest_upload"`
+}
+
+// NewEvidenceEvent จำนวนหลักฐานที่อัปโหลดหลังการเข้าชมครั้งล่าสุดของกิจกรรมหนึ่ง
+type NewEvidenceEvent struct {
+	EventID    uint      `json:"event_id"`
+	EventName  string    `json:"event_name"`
+	Uploads    uint      `json:"uploads"`
+	LastUpload time.Time `json:"last_upload"`
+}
+
+// OverdueReview การเข้าร่วมที่รอตรวจนานเกินกำหนด นับจากการอัปโหลดหลักฐานครั้งล่าสุด
+// ไฟล์ที่อัปโหลดก่อนมีระบบเวอร์ชันนับจากวันเริ่มกิจกรรม
+type OverdueReview struct {
+	EventID     uint      `json:"event_id"`
+	EventName   string    `json:"event_name"`
+	UserID      uint      `json:"user_id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+	WaitingDays uint      `json:"waiting_days"`
+}
+
+// DashboardCounts จำนวนสำหรับแสดงเป็น badge
+type DashboardCounts struct {
+	AwaitingReview uint `json:"awaiting_review"`
+	NewEvidence    uint `json:"new_evidence"`
+	Upcoming       uint `json:"upcoming"`
+	Overdue        uint `json:"overdue"`
+}
+
+// TeacherDashboard สรุปงานตรวจของกิจกรรมทั้งหมดที่อาจารย์สร้างหรือมีสิทธิ์รับรอง
+type TeacherDashboard struct {
+	LastVisit      *time.Time           `json:"last_visit"`
+	OverdueDays    uint                 `json:"overdue_days"`
+	Counts         DashboardCounts      `json:"counts"`
+	AwaitingReview []PendingReviewEvent `json:"awaiting_review"`
+	NewEvidence    []NewEvidenceEvent   `json:"new_evidence"`
+	Upcoming       []EventFillRate      `json:"upcoming"`
+	Overdue        []OverdueReview      `json:"overdue"`
+}
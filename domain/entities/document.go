@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// ชนิดของเอกสารที่ระบบออกให้
+const (
+	DocumentOutsideForm = "outside_form"
+	DocumentCertificate = "certificate"
+	DocumentTranscript  = "transcript"
+)
+
+// IssuedDocument ทะเบียนเอกสาร PDF ที่ระบบออก ใช้ตรวจสอบความถูกต้องผ่าน QR code
+// Checksum คือ SHA-256 ของไฟล์ PDF ที่ส่งให้ผู้ใช้ (รวม QR code แล้ว)
+// เอกสารหนึ่งฉบับต่อแหล่งที่มา (Type, OwnerID, EventID, SchoolYear) การออกซ้ำใช้รหัสเดิม
+// ข้อมูลในแถวนี้เป็นของการออกครั้งล่าสุด ส่วน hash ของทุกครั้งอยู่ใน DocumentIssue
+type IssuedDocument struct {
+	DocumentID      uint      `gorm:"primaryKey;autoIncrement" json:"document_id"`
+	Code            string    `gorm:"size:64;uniqueIndex;not null" json:"code"`
+	Type            string    `gorm:"size:32;not null;index:idx_document_source" json:"type"`
+	OwnerID         uint      `gorm:"not null;index;index:idx_document_source" json:"owner_id"`
+	Owner           Student   `gorm:"foreignKey:OwnerID;references:UserID" json:"-"`
+	EventID         uint      `gorm:"index:idx_document_source" json:"event_id"`
+	SchoolYear      uint      `gorm:"index:idx_document_source" json:"school_year"`
+	Title           string    `gorm:"size:255" json:"title"`
+	Details         string    `gorm:"type:text" json:"details"`
+	TemplateVersion string    `gorm:"size:32" json:"template_version"`
+	Checksum        string    `gorm:"size:64" json:"checksum"`
+	IssuedBy        uint      `json:"issued_by"`
+	IssuedAt        time.Time `json:"issued_at"`
+}
+
+// DocumentIssue ไฟล์แต่ละครั้งที่ออกภายใต้รหัสเอกสารเดียวกัน
+// การ render ซ้ำได้ไฟล์ที่ไม่ตรงกันทุกไบต์ จึงเก็บ hash ของทุกครั้งไว้ให้สำเนาที่ส่งให้ผู้ใช้ไปแล้วยังตรวจสอบผ่าน
+type DocumentIssue struct {
+	IssueID         uint      `gorm:"primaryKey;autoIncrement" json:"issue_id"`
+	DocumentID      uint      `gorm:"not null;uniqueIndex:idx_document_issue" json:"document_id"`
+	Checksum        string    `gorm:"size:64;not null;uniqueIndex:idx_document_issue" json:"checksum"`
+	TemplateVersion string    `gorm:"size:32" json:"template_version"`
+	Details         string    `gorm:"type:text" json:"details"`
+	IssuedBy        uint      `json:"issued_by"`
+	IssuedAt        time.Time `json:"issued_at"`
+}
@@ -18,6 +18,10 @@ type Event struct {
 	AllowAllYear   bool      `json:"allow_all_year"`
 	Status         bool      `gorm:"default:true" json:"status"`
 	Teacher        Teacher   `gorm:"foreignKey:Creator;references:UserID" json:"teacher"`
+	CategoryID     *uint     `gorm:"default:null;index" json:"category_id"`
+	Category       *Category `gorm:"foreignKey:CategoryID;references:CategoryID" json:"category"`
+	Tags           string    `gorm:"type:json" json:"tags"`
+	SDGs           string    `gorm:"type:json" json:"sdgs"`
 }
 
 type EventInside struct {
@@ -26,11 +30,26 @@ type EventInside struct {
 	// Event     Event   `gorm:"foreignKey:EventId;references:EventID" json:"event"`
 	Event     Event   `gorm:"foreignKey:EventId;references:EventID;constraint:OnDelete:CASCADE;" json:"event"`
 	Student   Student `gorm:"foreignKey:User;references:UserID" json:"student"`
+	// ผู้รับรองล่าสุด ว่างจนกว่าจะมีการรับรอง (ค่าเดียวกับ CertifiedBy)
 	Certifier uint    `gorm:"default:null" json:"certifier"`
 	Teacher   Teacher `gorm:"foreignKey:Certifier;references:UserID" json:"teacher"`
 	Status    bool    `json:"status"`
 	Comment   string  `json:"comment"`
 	FilePDF   string  `gorm:"size:255" json:"file_pdf"`
+	// ผู้ที่รับรองจริง (ผู้สร้าง ผู้จัดร่วม หรือเจ้าหน้าที่คณะ)
+	CertifiedBy *uint      `gorm:"default:null" json:"certified_by"`
+	CertifiedAt *time.Time `gorm:"default:null" json:"certified_at"`
+}
+
+// EventOrganizer อาจารย์ผู้จัดร่วมของกิจกรรม พร้อมสิทธิ์แยกแต่ละด้าน
+type EventOrganizer struct {
+	EventID          uint    `gorm:"primaryKey" json:"event_id"`
+	UserID           uint    `gorm:"primaryKey" json:"user_id"`
+	Event            Event   `gorm:"foreignKey:EventID;references:EventID;constraint:OnDelete:CASCADE;" json:"-"`
+	Teacher          Teacher `gorm:"foreignKey:UserID;references:UserID" json:"teacher"`
+	CanEdit          bool    `json:"can_edit"`
+	CanCertify       bool    `json:"can_certify"`
+	CanViewChecklist bool    `json:"can_view_checklist"`
 }
 
 type EventOutside struct {
@@ -48,6 +67,47 @@ type EventOutside struct {
 	// Status      bool      `json:"status"`
 	// Comment     string    `json:"comment"`
 	FilePDF string `gorm:"size:255" json:"file_pdf"`
+	// หมวดหมู่ที่นักศึกษาระบุเอง (ไม่บังคับ)
+	CategoryID *uint     `gorm:"default:null;index" json:"category_id"`
+	Category   *Category `gorm:"foreignKey:CategoryID;references:CategoryID" json:"category"`
+}
+
+// EvidenceVersion ประวัติไฟล์หลักฐานของกิจกรรมภายใน ไฟล์ปัจจุบันคือ EventInside.FilePDF
+// เวอร์ชันที่ถูกแทนที่จะถูกลบไฟล์ออกเมื่อเกินระยะเวลาเก็บรักษา แต่ยังเก็บประวัติไว้
+type EvidenceVersion struct {
+	VersionID    uint       `gorm:"primaryKey;autoIncrement" json:"version_id"`
+	EventID      uint       `gorm:"not null;index:idx_evidence_owner" json:"event_id"`
+	UserID       uint       `gorm:"not null;index:idx_evidence_owner" json:"user_id"`
+	Version      uint       `gorm:"not null" json:"version"`
+	FileKey      string     `gorm:"size:255;not null" json:"-"`
+	Size         int64      `json:"size"`
+	Checksum     string     `gorm:"size:64" json:"checksum"`
+	UploadedAt   time.Time  `json:"uploaded_at"`
+	SupersededAt *time.Time `gorm:"index" json:"superseded_at"`
+	PurgedAt     *time.Time `json:"purged_at"`
+}
+
+// ประเภทของการเข้าร่วมที่ไฟล์แนบผูกอยู่
+const (
+	AttachmentInside  = "inside"
+	AttachmentOutside = "outside"
+)
+
+// Attachment ไฟล์หลักฐานแนบของการเข้าร่วมกิจกรรม แนบได้หลายไฟล์ (PDF หรือรูปภาพ)
+type Attachment struct {
+	AttachmentID uint      `gorm:"primaryKey;autoIncrement" json:"attachment_id"`
+	Kind         string    `gorm:"size:16;not null;index:idx_attachment_owner" json:"kind"`
+	EventID      uint      `gorm:"not null;index:idx_attachment_owner" json:"event_id"`
+	UserID       uint      `gorm:"not null;index:idx_attachment_owner" json:"user_id"`
+	Type         string    `gorm:"size:16;not null" json:"type"`
+	ContentType  string    `gorm:"size:64;not null" json:"content_type"`
+	FileName     string    `gorm:"size:255" json:"file_name"`
+	Size         int64     `json:"size"`
+	Checksum     string    `gorm:"size:64" json:"checksum"`
+	FileKey      string    `gorm:"size:255;not null" json:"-"`
+	ThumbnailKey string    `gorm:"size:255" json:"-"`
+	UploadedBy   uint      `json:"uploaded_by"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Done struct {
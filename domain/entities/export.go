@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// ChecklistExport ผู้เข้าร่วมกิจกรรมหนึ่งคนสำหรับส่งออกรายชื่อ
+type ChecklistExport struct {
+	Code        string
+	TitleName   string
+	FirstName   string
+	LastName    string
+	BranchName  string
+	FacultyName string
+	Status      bool
+	Comment     string
+	CertifiedAt *time.Time
+	HasFile     bool
+}
+
+// ParticipationExport การเข้าร่วมกิจกรรมหนึ่งรายการ (Kind เป็น inside หรือ outside)
+type ParticipationExport struct {
+	Code         string
+	TitleName    string
+	FirstName    string
+	LastName     string
+	BranchName   string
+	FacultyName  string
+	Kind         string
+	EventName    string
+	CategoryName string
+	StartDate    time.Time
+	WorkingHour  uint
+	Approved     bool
+	Rejected     bool
+}
+
+// StudentHoursExport ชั่วโมงรวมของนักศึกษาหนึ่งคนในปีการศึกษา
+type StudentHoursExport struct {
+	UserID       uint
+	Code         string
+	TitleName    string
+	FirstName    string
+	LastName     string
+	BranchID     uint
+	FacultyID    uint
+	ClassYear    uint
+	BranchName   string
+	FacultyName  string
+	InsideHours  uint
+	PendingHours uint
+	OutsideHours uint
+}
@@ -15,3 +15,11 @@ type Branch struct {
 	FacultyId  uint    `gorm:"not null" json:"faculty_id"`
 	Faculty    Faculty `gorm:"foreignKey:FacultyId;references:FacultyID" json:"faculty"`
 }
+
+// FacultyStaff เจ้าหน้าที่ประจำคณะ (หนึ่งคณะมีได้หลายคน)
+type FacultyStaff struct {
+	FacultyID uint    `gorm:"primaryKey" json:"faculty_id"`
+	UserID    uint    `gorm:"primaryKey" json:"user_id"`
+	Faculty   Faculty `gorm:"foreignKey:FacultyID;references:FacultyID;constraint:OnDelete:CASCADE;" json:"faculty"`
+	Teacher   Teacher `gorm:"foreignKey:UserID;references:UserID" json:"teacher"`
+}
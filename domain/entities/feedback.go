@@ -0,0 +1,68 @@
+package entities
+
+import "time"
+
+// EventFeedback คะแนนและความคิดเห็นต่อกิจกรรมภายในจากนักศึกษาที่ได้รับการรับรองแล้ว คนละหนึ่งรายการต่อกิจกรรม
+// ความคิดเห็นที่ถูกซ่อนโดยผู้ดูแลระบบยังนับคะแนนในค่าเฉลี่ย แต่จะไม่แสดงให้ผู้จัดกิจกรรมเห็น
+type EventFeedback struct {
+	FeedbackID   uint       `gorm:"primaryKey;autoIncrement" json:"feedback_id"`
+	EventID      uint       `gorm:"not null;uniqueIndex:idx_feedback_owner" json:"event_id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_feedback_owner" json:"user_id"`
+	Event        Event      `gorm:"foreignKey:EventID;references:EventID;constraint:OnDelete:CASCADE;" json:"-"`
+	Rating       uint       `gorm:"not null" json:"rating"`
+	Comment      string     `gorm:"size:1000" json:"comment"`
+	Hidden       bool       `gorm:"default:false" json:"hidden"`
+	HiddenBy     *uint      `gorm:"default:null" json:"hidden_by"`
+	HiddenAt     *time.Time `gorm:"default:null" json:"hidden_at"`
+	HiddenReason string     `gorm:"size:255" json:"hidden_reason"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// FeedbackSummary คะแนนรวมของกิจกรรม Stars คือจำนวนคะแนน 1 ถึง 5 ดาวตามลำดับ
+type FeedbackSummary struct {
+	EventID uint    `json:"event_id"`
+	Count   uint    `json:"count"`
+	Average float64 `json:"average"`
+	Stars   [5]uint `json:"stars"`
+}
+
+// FeedbackComment ความคิดเห็นที่แสดงให้ผู้จัดกิจกรรมเห็น โดยไม่ระบุตัวนักศึกษา
+type FeedbackComment struct {
+	Rating    uint      `json:"rating"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventFeedbackReport คะแนนรวมและความคิดเห็นที่ไม่ถูกซ่อนของกิจกรรมหนึ่ง
+type EventFeedbackReport struct {
+	Summary  FeedbackSummary   `json:"summary"`
+	Comments []FeedbackComment `json:"comments"`
+}
+
+// FeedbackReview ความคิดเห็นหนึ่งรายการสำหรับผู้ดูแลระบบตรวจสอบ พร้อมชื่อกิจกรรมและนักศึกษา
+type FeedbackReview struct {
+	FeedbackID   uint       `json:"feedback_id"`
+	EventID      uint       `json:"event_id"`
+	EventName    string     `json:"event_name"`
+	SchoolYear   uint       `json:"school_year"`
+	UserID       uint       `json:"user_id"`
+	Code         string     `json:"code"`
+	Name         string     `json:"name"`
+	Rating       uint       `json:"rating"`
+	Comment      string     `json:"comment"`
+	Hidden       bool       `json:"hidden"`
+	HiddenBy     *uint      `json:"hidden_by"`
+	HiddenAt     *time.Time `json:"hidden_at"`
+	HiddenReason string     `json:"hidden_reason"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// FeedbackPage ผลการค้นหาความคิดเห็นแบบแบ่งหน้า
+type FeedbackPage struct {
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+	Rows  []FeedbackReview `json:"rows"`
+}
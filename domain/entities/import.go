@@ -0,0 +1,51 @@
+package entities
+
+// สถานะของแต่ละแถวในรายงานการนำเข้า
+const (
+	ImportValid   = "valid"   // ผ่านการตรวจ (dry-run)
+	ImportCreated = "created" // สร้างใหม่แล้ว
+	ImportUpdated = "updated" // แก้ไขข้อมูลเดิมแล้ว
+	ImportInvalid = "invalid" // ข้อมูลไม่ถูกต้อง ไม่ได้บันทึก
+	ImportSkipped = "skipped" // ไม่ได้บันทึก เพราะตรงกับข้อมูลเดิมแล้ว หรือแถวอื่นผิดพลาดในโหมด atomic
+	ImportFailed  = "failed"  // บันทึกไม่สำเร็จ
+)
+
+// สิ่งที่จะทำกับแถวนั้นเมื่อบันทึก (แสดงใน dry-run ด้วย)
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionNone   = "none"
+)
+
+// โหมดการบันทึก
+const (
+	ImportAtomic = "atomic" // ทั้งไฟล์ใน transaction เดียว ผิดแถวเดียวไม่บันทึกเลย
+	ImportChunk  = "chunk"  // บันทึกเป็นชุด ข้ามแถวที่ไม่ถูกต้อง
+)
+
+// ImportRowResult ผลของแถวหนึ่งในไฟล์ Key คือรหัสที่ใช้อ้างอิงแถว (เช่นรหัสนักศึกษา)
+// Password มีค่าเฉพาะรหัสผ่านที่ระบบสุ่มให้และไม่ได้ส่งทางอีเมล
+type ImportRowResult struct {
+	Row        int      `json:"row"`
+	Key        string   `json:"key"`
+	Status     string   `json:"status"`
+	Action     string   `json:"action,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+	Password   string   `json:"password,omitempty"`
+	EmailSent  bool     `json:"email_sent,omitempty"`
+	EmailError string   `json:"email_error,omitempty"`
+}
+
+// ImportReport รายงานการนำเข้าทั้งไฟล์
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Mode    string            `json:"mode"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,87 @@
+package entities
+
+import "time"
+
+// ประเภทของข้อกำหนดในผลการประเมิน
+const (
+	RequirementTotal    = "total"
+	RequirementCategory = "category"
+)
+
+// RequirementSet เกณฑ์ชั่วโมงกิจกรรมของกลุ่มนักศึกษา กำหนดกลุ่มด้วยคณะ สาขา และชั้นปี (nil คือทุกกลุ่ม)
+// ClassYear คือชั้นปีปัจจุบันของนักศึกษา (students.year) ไม่ใช่รุ่นที่เข้าศึกษา
+// เกณฑ์ของชั้นปีที่ 1 จึงใช้กับนักศึกษาชั้นปีที่ 1 ของแต่ละปี และนักศึกษาจะเปลี่ยนไปใช้เกณฑ์ของชั้นปีถัดไปเมื่อเลื่อนชั้น
+// นักศึกษาที่ตรงกับหลายเกณฑ์ใช้เกณฑ์ที่เจาะจงที่สุด (สาขา > คณะ > ชั้นปี)
+// PerYear เป็น true คือชั่วโมงต่อปีการศึกษา false คือชั่วโมงตลอดหลักสูตร
+type RequirementSet struct {
+	RequirementSetID uint              `gorm:"primaryKey;autoIncrement" json:"requirement_set_id"`
+	Name             string            `gorm:"size:100;not null" json:"name"`
+	Description      string            `json:"description"`
+	FacultyID        *uint             `gorm:"index" json:"faculty_id"`
+	Faculty          *Faculty          `gorm:"foreignKey:FacultyID;references:FacultyID" json:"faculty,omitempty"`
+	BranchID         *uint             `gorm:"index" json:"branch_id"`
+	Branch           *Branch           `gorm:"foreignKey:BranchID;references:BranchID" json:"branch,omitempty"`
+	ClassYear        *uint             `json:"class_year"`
+	PerYear          bool              `gorm:"not null" json:"per_year"`
+	TotalHours       uint              `gorm:"not null" json:"total_hours"`
+	Rules            []RequirementRule `gorm:"foreignKey:RequirementSetID;constraint:OnDelete:CASCADE" json:"rules"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// RequirementRule ชั่วโมงขั้นต่ำในหมวดหมู่หนึ่ง ชั่วโมงเหล่านี้นับรวมในชั่วโมงรวมของเกณฑ์ด้วย
+type RequirementRule struct {
+	RuleID           uint      `gorm:"primaryKey;autoIncrement" json:"rule_id"`
+	RequirementSetID uint      `gorm:"not null;uniqueIndex:idx_requirement_rule" json:"-"`
+	CategoryID       uint      `gorm:"not null;uniqueIndex:idx_requirement_rule" json:"category_id"`
+	Category         *Category `gorm:"foreignKey:CategoryID;references:CategoryID" json:"category,omitempty"`
+	MinHours         uint      `gorm:"not null" json:"min_hours"`
+}
+
+// CategoryHours ชั่วโมงของนักศึกษาในหมวดหมู่หนึ่ง CategoryID เป็น 0 คือไม่ระบุหมวดหมู่
+type CategoryHours struct {
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	EarnedHours  uint   `json:"earned_hours"`
+	PendingHours uint   `json:"pending_hours"`
+}
+
+// StudentCategoryHours ชั่วโมงของนักศึกษาในหมวดหมู่หนึ่ง ทั้งของปีการศึกษาที่เลือกและของทุกปี
+type StudentCategoryHours struct {
+	UserID            uint
+	CategoryID        uint
+	CategoryName      string
+	EarnedHours       uint
+	PendingHours      uint
+	TotalEarnedHours  uint
+	TotalPendingHours uint
+}
+
+// RuleResult ผลของข้อกำหนดหนึ่งข้อ (ชั่วโมงรวมหรือชั่วโมงในหมวดหมู่) พร้อมคำอธิบาย
+type RuleResult struct {
+	Kind          string `json:"kind"`
+	CategoryID    uint   `json:"category_id,omitempty"`
+	CategoryName  string `json:"category_name,omitempty"`
+	RequiredHours uint   `json:"required_hours"`
+	EarnedHours   uint   `json:"earned_hours"`
+	PendingHours  uint   `json:"pending_hours"`
+	MissingHours  uint   `json:"missing_hours"`
+	Met           bool   `json:"met"`
+	Explanation   string `json:"explanation"`
+}
+
+// RequirementEvaluation ผลการประเมินชั่วโมงของนักศึกษาตามเกณฑ์ SchoolYear เป็น 0 คือตลอดหลักสูตร
+// RequirementSetID เป็น nil คือไม่มีเกณฑ์ที่ตรงกับนักศึกษาและใช้ชั่วโมงขั้นต่ำต่อปีจากการตั้งค่าระบบ
+type RequirementEvaluation struct {
+	UserID             uint            `json:"user_id"`
+	Code               string          `json:"code"`
+	Name               string          `json:"name"`
+	SchoolYear         uint            `json:"school_year"`
+	RequirementSetID   *uint           `json:"requirement_set_id"`
+	RequirementSetName string          `json:"requirement_set_name"`
+	Met                bool            `json:"met"`
+	EarnedHours        uint            `json:"earned_hours"`
+	PendingHours       uint            `json:"pending_hours"`
+	Rules              []RuleResult    `json:"rules"`
+	Categories         []CategoryHours `json:"categories"`
+	Summary            string          `json:"summary"`
+}
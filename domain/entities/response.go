@@ -26,6 +26,11 @@ type EventResponse struct {
 		Phone     string `json:"phone"`
 		Code      string `json:"code"`
 	} `json:"creator"`
+	Category *CategoryResponse `json:"category"`
+	Tags     []string          `json:"tags"`
+	SDGs     []uint            `json:"sdgs"`
+	// Rating คะแนนรวมจากผู้เข้าร่วม มีเฉพาะในรายการกิจกรรมของผู้จัด
+	Rating *FeedbackSummary `json:"rating,omitempty"`
 }
 
 type OutsideRequest struct {
@@ -35,6 +40,7 @@ type OutsideRequest struct {
 	SchoolYear  uint   `json:"school_year"`
 	WorkingHour uint   `json:"working_hour"`
 	Intendant   string `json:"intendent"`
+	CategoryID  *uint  `json:"category_id"`
 }
 type StudentResponse struct {
 	UserID      uint   `json:"user_id"`
@@ -57,6 +63,8 @@ type OutsideResponse struct {
 	SchoolYear  uint            `json:"school_year"`
 	WorkingHour uint            `json:"working_hour"`
 	Intendant   string          `json:"intendent"`
+	CategoryID   *uint           `json:"category_id"`
+	CategoryName string          `json:"category_name"`
 	Student     StudentResponse `json:"student"`
 }
 
@@ -71,6 +79,8 @@ type MyChecklist struct {
 	Status    bool   `json:"status"`
 	Comment   string `json:"comment"`
 	FilePDF   string `json:"file_pdf"`
+	CertifiedBy *uint      `json:"certified_by"`
+	CertifiedAt *time.Time `json:"certified_at"`
 }
 
 type MyOutside struct {
@@ -85,6 +95,8 @@ type MyOutside struct {
 	// Status      bool   `json:"status"`
 	// Comment     string `json:"comment"`
 	FilePDF   string  `json:"file_pdf"`
+	CategoryID   *uint  `json:"category_id"`
+	CategoryName string `json:"category_name"`
 }
 
 type MyInside struct {
@@ -96,6 +108,55 @@ type MyInside struct {
 	WorkingHour uint   `json:"working_hour"`
 	SchoolYear  uint   `json:"school_year"`
 	Status      bool   `json:"status"`
+	// ผู้รับรองตรวจแล้วแต่ไม่อนุมัติ ชั่วโมงไม่นับทั้งที่ได้รับและที่รอรับรอง
+	Rejected    bool   `json:"rejected"`
 	Comment     string `json:"comment"`
 	FilePDF   string  `json:"file_pdf"`
+	CategoryID   *uint  `json:"category_id"`
+	CategoryName string `json:"category_name"`
+}
+
+// แหล่งที่มาของการอ้างอิงไฟล์ใน storage
+const (
+	FileRefInside     = "event_inside"
+	FileRefOutside    = "event_outside"
+	FileRefAttachment = "attachment"
+	FileRefThumbnail  = "attachment_thumbnail"
+	FileRefVersion    = "evidence_version"
+)
+
+// FileReference แถวในฐานข้อมูลที่อ้างถึงไฟล์ใน storage
+type FileReference struct {
+	Source  string `json:"source"`
+	ID      uint   `json:"id,omitempty"`
+	EventID uint   `json:"event_id,omitempty"`
+	UserID  uint   `json:"user_id,omitempty"`
+	Key     string `json:"key"`
+}
+
+// StorageReport ผลการตรวจความสอดคล้องระหว่าง storage กับฐานข้อมูล
+type StorageReport struct {
+	DryRun       bool            `json:"dry_run"`
+	ScannedFiles int             `json:"scanned_files"`
+	References   int             `json:"references"`
+	Orphans      []string        `json:"orphans"`
+	Missing      []FileReference `json:"missing"`
+	Removed      int             `json:"removed"`
+	Repaired     int             `json:"repaired"`
+}
+
+// DocumentVerification ผลการตรวจสอบเอกสารสำหรับหน้าตรวจสอบสาธารณะ
+// Matches มีค่าเมื่อส่งไฟล์มาตรวจ ว่าไฟล์ตรงกับที่ระบบออกให้หรือไม่
+type DocumentVerification struct {
+	Valid           bool      `json:"valid"`
+	Code            string    `json:"code"`
+	Type            string    `json:"type,omitempty"`
+	Title           string    `json:"title,omitempty"`
+	Details         string    `json:"details,omitempty"`
+	OwnerName       string    `json:"owner_name,omitempty"`
+	StudentCode     string    `json:"student_code,omitempty"`
+	TemplateVersion string    `json:"template_version,omitempty"`
+	Checksum        string    `json:"checksum,omitempty"`
+	IssuedAt        time.Time `json:"issued_at,omitempty"`
+	Matches         *bool     `json:"matches,omitempty"`
 }
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// กลุ่มของสถิติการเข้าร่วม
+const (
+	GroupByFaculty    = "faculty"
+	GroupByBranch     = "branch"
+	GroupByYear       = "year"
+	GroupBySchoolYear = "school_year"
+)
+
+// participationUnion การเข้าร่วมทั้งภายในและภายนอก ปีการศึกษา 0 คือทุกปี (ใช้ parameter ปีการศึกษา 4 ตัว)
+// rejected คือการเข้าร่วมที่ผู้รับรองไม่อนุมัติ ไม่นับเป็นชั่วโมงที่รอรับรอง
+const participationUnion = `
+	SELECT ei.user AS user_id, 'inside' AS kind, e.school_year, e.working_hour AS hours, ei.status AS approved,
+		(NOT ei.status AND ei.certified_at IS NOT NULL) AS rejected, e.category_id
+	FROM event_insides ei JOIN events e ON e.event_id = ei.event_id
+	WHERE (? = 0 OR e.school_year = ?)
+	UNION ALL
+	SELECT eo.user AS user_id, 'outside' AS kind, eo.school_year, eo.working_hour AS hours, TRUE AS approved, FALSE AS rejected, eo.category_id
+	FROM event_outsides eo
+	WHERE (? = 0 OR eo.school_year = ?)`
+
+// AnalyticsRepository สถิติรวมสำหรับผู้ดูแลระบบ คำนวณด้วย SQL aggregate ปีการศึกษา 0 คือทุกปี
+type AnalyticsRepository interface {
+	ParticipationStats(schoolYear uint, groupBy string) ([]entities.ParticipationStat, error)
+	EventFillRates(schoolYear uint) ([]entities.EventFillRate, error)
+	TopOrganizers(schoolYear uint, limit int) ([]entities.OrganizerStat, error)
+	TurnaroundByFaculty(schoolYear uint) ([]entities.TurnaroundStat, error)
+	StudentScopes() ([]entities.StudentScope, error)
+	CategoryStats(schoolYear uint) ([]entities.CategoryStat, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) ParticipationStats(schoolYear uint, groupBy string) ([]entities.ParticipationStat, error) {
+	var group string
+	switch groupBy {
+	case GroupByFaculty:
+		group = "f.faculty_id AS group_id, f.faculty_name AS group_name"
+	case GroupByBranch:
+		group = "b.branch_id AS group_id, b.branch_name AS group_name"
+	case GroupByYear:
+		group = "s.year AS group_id, CAST(s.year AS CHAR) AS group_name"
+	case GroupBySchoolYear:
+		group = "p.school_year AS group_id, CAST(p.school_year AS CHAR) AS group_name"
+	default:
+		return nil, fmt.Errorf("unknown group %q", groupBy)
+	}
+	var stats []entities.ParticipationStat
+	err := r.db.Raw(`SELECT `+group+`,
+		COUNT(*) AS participations,
+		SUM(p.kind = 'inside') AS inside,
+		SUM(p.kind = 'outside') AS outside,
+		COUNT(DISTINCT p.user_id) AS students,
+		COALESCE(SUM(CASE WHEN p.approved THEN p.hours ELSE 0 END), 0) AS approved_hours,
+		COALESCE(SUM(CASE WHEN p.approved OR p.rejected THEN 0 ELSE p.hours END), 0) AS pending_hours
+	FROM (`+participationUnion+`) p
+	JOIN students s ON s.user_id = p.user_id
+	JOIN branches b ON b.branch_id = s.branch_id
+	JOIN faculties f ON f.faculty_id = b.faculty_id
+	GROUP BY group_id, group_name
+	ORDER BY group_id`, schoolYear, schoolYear, schoolYear, schoolYear).Scan(&stats).Error
+	return stats, err
+}
+
+// EventFillRates ที่นั่งทั้งหมดของกิจกรรมคือ free_space (ที่นั่งที่ยังว่าง) รวมกับผู้ที่เข้าร่วมแล้ว
+func (r *analyticsRepository) EventFillRates(schoolYear uint) ([]entities.EventFillRate, error) {
+	var rates []entities.EventFillRate
+	err := r.db.Raw(`SELECT e.event_id, e.event_name, e.start_date,
+		e.free_space + COUNT(ei.user) AS capacity,
+		COUNT(ei.user) AS joined,
+		COALESCE(SUM(ei.status), 0) AS approved
+	FROM events e
+	LEFT JOIN event_insides ei ON ei.event_id = e.event_id
+	WHERE (? = 0 OR e.school_year = ?)
+	GROUP BY e.event_id, e.event_name, e.start_date, e.free_space
+	ORDER BY e.start_date`, schoolYear, schoolYear).Scan(&rates).Error
+	return rates, err
+}
+
+// TopOrganizers อาจารย์ที่สร้างหรือร่วมจัดกิจกรรมมากที่สุด
+func (r *analyticsRepository) TopOrganizers(schoolYear uint, limit int) ([]entities.OrganizerStat, error) {
+	var stats []entities.OrganizerStat
+	err := r.db.Raw(`SELECT t.user_id, CONCAT(t.title_name, t.first_name, ' ', t.last_name) AS name,
+		SUM(o.created) AS created,
+		SUM(1 - o.created) AS co_organized,
+		COALESCE(SUM(p.joined), 0) AS participants,
+		COALESCE(SUM(p.approved), 0) AS approved
+	FROM (
+		SELECT e.event_id, e.creator AS user_id, 1 AS created
+		FROM events e
+		WHERE (? = 0 OR e.school_year = ?)
+		UNION ALL
+		SELECT eo.event_id, eo.user_id, 0 AS created
+		FROM event_organizers eo JOIN events e ON e.event_id = eo.event_id
+		WHERE (? = 0 OR e.school_year = ?) AND eo.user_id <> e.creator
+	) o
+	JOIN teachers t ON t.user_id = o.user_id
+	LEFT JOIN (
+		SELECT event_id, COUNT(*) AS joined, SUM(status) AS approved
+		FROM event_insides GROUP BY event_id
+	) p ON p.event_id = o.event_id
+	GROUP BY t.user_id, t.title_name, t.first_name, t.last_name
+	ORDER BY COUNT(*) DESC, participants DESC
+	LIMIT ?`, schoolYear, schoolYear, schoolYear, schoolYear, limit).Scan(&stats).Error
+	return stats, err
+}
+
+// TurnaroundByFaculty นับจากไฟล์หลักฐานเวอร์ชันล่าสุดที่ส่งก่อนการรับรอง การเข้าร่วมที่ไม่มีหลักฐานไม่นับ
+func (r *analyticsRepository) TurnaroundByFaculty(schoolYear uint) ([]entities.TurnaroundStat, error) {
+	var stats []entities.TurnaroundStat
+	err := r.db.Raw(`SELECT f.faculty_id AS group_id, f.faculty_name AS group_name,
+		COUNT(*) AS certified,
+		AVG(t.minutes) / 60 AS average_hours,
+		MIN(t.minutes) / 60 AS min_hours,
+		MAX(t.minutes) / 60 AS max_hours
+	FROM (
+		SELECT ei.user AS user_id, TIMESTAMPDIFF(MINUTE, MAX(v.uploaded_at), ei.certified_at) AS minutes
+		FROM event_insides ei
+		JOIN events e ON e.event_id = ei.event_id
+		JOIN evidence_versions v ON v.event_id = ei.event_id AND v.user_id = ei.user AND v.uploaded_at <= ei.certified_at
+		WHERE ei.status AND ei.certified_at IS NOT NULL AND (? = 0 OR e.school_year = ?)
+		GROUP BY ei.event_id, ei.user, ei.certified_at
+	) t
+	JOIN students s ON s.user_id = t.user_id
+	JOIN branches b ON b.branch_id = s.branch_id
+	JOIN faculties f ON f.faculty_id = b.faculty_id
+	GROUP BY f.faculty_id, f.faculty_name
+	ORDER BY f.faculty_id`, schoolYear, schoolYear).Scan(&stats).Error
+	return stats, err
+}
+
+// StudentScopes นักศึกษาทุกคนพร้อมคณะ สาขา และชั้นปี เรียงตามคณะ
+func (r *analyticsRepository) StudentScopes() ([]entities.StudentScope, error) {
+	var scopes []entities.StudentScope
+	err := r.db.Raw(`SELECT s.user_id, f.faculty_id, f.faculty_name, s.branch_id, s.year AS class_year
+	FROM students s
+	JOIN branches b ON b.branch_id = s.branch_id
+	JOIN faculties f ON f.faculty_id = b.faculty_id
+	ORDER BY f.faculty_id, s.user_id`).Scan(&scopes).Error
+	return scopes, err
+}
+
+// CategoryStats การเข้าร่วมแยกตามหมวดหมู่ กิจกรรมที่ไม่ระบุหมวดหมู่รวมอยู่ที่ category_id 0
+func (r *analyticsRepository) CategoryStats(schoolYear uint) ([]entities.CategoryStat, error) {
+	var stats []entities.CategoryStat
+	err := r.db.Raw(`SELECT COALESCE(c.category_id, 0) AS category_id,
+		COALESCE(c.name, 'ไม่ระบุหมวดหมู่') AS category_name,
+		COUNT(*) AS participations,
+		SUM(p.kind = 'inside') AS inside,
+		SUM(p.kind = 'outside') AS outside,
+		COUNT(DISTINCT p.user_id) AS students,
+		COALESCE(SUM(CASE WHEN p.approved THEN p.hours ELSE 0 END), 0) AS approved_hours,
+		COALESCE(SUM(CASE WHEN p.approved OR p.rejected THEN 0 ELSE p.hours END), 0) AS pending_hours
+	FROM (`+participationUnion+`) p
+	LEFT JOIN categories c ON c.category_id = p.category_id
+	GROUP BY c.category_id, c.name
+	ORDER BY participations DESC, category_id`, schoolYear, schoolYear, schoolYear, schoolYear).Scan(&stats).Error
+	return stats, err
+}
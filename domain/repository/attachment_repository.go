@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAttachmentLimit ใช้เมื่อจำนวนไฟล์แนบของการเข้าร่วมครบตามที่กำหนดแล้ว
+var ErrAttachmentLimit = errors.New("attachment limit reached")
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *entities.Attachment, limit int) error
+	GetAttachments(kind string, eventID uint, userID uint) ([]entities.Attachment, error)
+	GetAttachment(id uint) (*entities.Attachment, error)
+	DeleteAttachment(id uint) error
+	DeleteAttachments(kind string, eventID uint, userID uint) ([]entities.Attachment, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+// CreateAttachment บันทึกไฟล์แนบ โดยนับจำนวนเดิมใน transaction เดียวกันกันการอัปโหลดพร้อมกันเกิน limit
+func (r *attachmentRepository) CreateAttachment(attachment *entities.Attachment, limit int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entities.Attachment{}).
+			Where("kind = ? AND event_id = ? AND user_id = ?", attachment.Kind, attachment.EventID, attachment.UserID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if limit > 0 && count >= int64(limit) {
+			return fmt.Errorf("%w: maximum %d files", ErrAttachmentLimit, limit)
+		}
+		return tx.Create(attachment).Error
+	})
+}
+
+func (r *attachmentRepository) GetAttachments(kind string, eventID uint, userID uint) ([]entities.Attachment, error) {
+	var attachments []entities.Attachment
+	if err := r.db.Where("kind = ? AND event_id = ? AND user_id = ?", kind, eventID, userID).
+		Order("created_at").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) GetAttachment(id uint) (*entities.Attachment, error) {
+	var attachment entities.Attachment
+	if err := r.db.First(&attachment, "attachment_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("attachment with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to retrieve attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(id uint) error {
+	return r.db.Delete(&entities.Attachment{}, "attachment_id = ?", id).Error
+}
+
+// DeleteAttachments ลบไฟล์แนบทั้งหมดของการเข้าร่วม คืนรายการที่ลบเพื่อให้ผู้เรียกลบไฟล์ใน storage ต่อ
+func (r *attachmentRepository) DeleteAttachments(kind string, eventID uint, userID uint) ([]entities.Attachment, error) {
+	attachments, err := r.GetAttachments(kind, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if err := r.db.Where("kind = ? AND event_id = ? AND user_id = ?", kind, eventID, userID).
+		Delete(&entities.Attachment{}).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
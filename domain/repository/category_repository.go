@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	CreateCategory(category *entities.Category) error
+	GetAllCategories() ([]entities.Category, error)
+	GetCategory(id uint) (*entities.Category, error)
+	GetCategoryByName(name string) (*entities.Category, error)
+	UpdateCategory(category *entities.Category) error
+	DeleteCategory(id uint) error
+	CountUsage(id uint) (int64, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) CreateCategory(category *entities.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) GetAllCategories() ([]entities.Category, error) {
+	var categories []entities.Category
+	if err := r.db.Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) GetCategory(id uint) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetCategoryByName(name string) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.Where("name = ?", name).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) UpdateCategory(category *entities.Category) error {
+	return r.db.Save(category).Error
+}
+
+func (r *categoryRepository) DeleteCategory(id uint) error {
+	return r.db.Delete(&entities.Category{}, id).Error
+}
+
+// CountUsage จำนวนกิจกรรมภายในและภายนอกที่อยู่ในหมวดหมู่ รวมกับข้อกำหนดชั่วโมงที่อ้างถึงหมวดหมู่
+func (r *categoryRepository) CountUsage(id uint) (int64, error) {
+	var inside, outside, rules int64
+	if err := r.db.Model(&entities.Event{}).Where("category_id = ?", id).Count(&inside).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&entities.EventOutside{}).Where("category_id = ?", id).Count(&outside).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&entities.RequirementRule{}).Where("category_id = ?", id).Count(&rules).Error; err != nil {
+		return 0, err
+	}
+	return inside + outside + rules, nil
+}
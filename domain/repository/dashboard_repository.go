@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reviewerEvents กิจกรรมที่ผู้ใช้สร้าง เป็นผู้จัดร่วมที่มีสิทธิ์รับรอง หรืออยู่ใน staffEvents ของคณะที่ผู้ใช้เป็นเจ้าหน้าที่
+// (ใช้ parameter รหัสผู้ใช้ 2 ตัวตามด้วย staffEvents)
+const reviewerEvents = `(e.creator = ? OR e.event_id IN (
+	SELECT event_id FROM event_organizers WHERE user_id = ? AND can_certify) OR e.event_id IN ?)`
+
+// pendingReviews การเข้าร่วมที่ส่งหลักฐานแล้วและยังไม่ได้ตรวจ หรือส่งหลักฐานใหม่หลังถูกตีกลับ
+// uploaded_at คือเวลาอัปโหลดเวอร์ชันล่าสุด ไฟล์ที่ไม่มีประวัติเวอร์ชันใช้วันเริ่มกิจกรรม
+const pendingReviews = `
+	SELECT ei.event_id, ei.user AS user_id, COALESCE(v.uploaded_at, e.start_date) AS uploaded_at
+	FROM event_insides ei
+	JOIN events e ON e.event_id = ei.event_id
+	LEFT JOIN (
+		SELECT event_id, user_id, MAX(uploaded_at) AS uploaded_at
+		FROM evidence_versions GROUP BY event_id, user_id
+	) v ON v.event_id = ei.event_id AND v.user_id = ei.user
+	WHERE ` + reviewerEvents + `
+		AND NOT ei.status AND ei.file_pdf <> ''
+		AND (ei.certified_at IS NULL OR ei.certified_at < v.uploaded_at)`
+
+// DashboardRepository สรุปงานตรวจของกิจกรรมที่อาจารย์สร้างหรือมีสิทธิ์รับรอง
+// staffEvents คือกิจกรรมของคณะที่อาจารย์เป็นเจ้าหน้าที่ ซึ่งรับรองได้เช่นกัน
+type DashboardRepository interface {
+	PendingByEvent(userID uint, staffEvents []uint) ([]entities.PendingReviewEvent, error)
+	NewEvidenceByEvent(userID uint, staffEvents []uint, since time.Time) ([]entities.NewEvidenceEvent, error)
+	UpcomingEvents(userID uint, staffEvents []uint, from time.Time) ([]entities.EventFillRate, error)
+	CountOverdue(userID uint, staffEvents []uint, before time.Time) (uint, error)
+	OverdueReviews(userID uint, staffEvents []uint, before time.Time, limit int) ([]entities.OverdueReview, error)
+	GetLastVisit(userID uint) (*entities.DashboardVisit, error)
+	SaveVisit(visit *entities.DashboardVisit) error
+}
+
+type dashboardRepository struct {
+	db *gorm.DB
+}
+
+func NewDashboardRepository(db *gorm.DB) DashboardRepository {
+	return &dashboardRepository{db: db}
+}
+
+func (r *dashboardRepository) PendingByEvent(userID uint, staffEvents []uint) ([]entities.PendingReviewEvent, error) {
+	var events []entities.PendingReviewEvent
+	err := r.db.Raw(`SELECT e.event_id, e.event_name, e.start_date,
+		COUNT(*) AS pending,
+		MIN(p.uploaded_at) AS oldest_upload
+	FROM (`+pendingReviews+`) p
+	JOIN events e ON e.event_id = p.event_id
+	GROUP BY e.event_id, e.event_name, e.start_date
+	ORDER BY oldest_upload`, userID, userID, staffEvents).Scan(&events).Error
+	return events, err
+}
+
+// NewEvidenceByEvent นับทุกเวอร์ชันที่อัปโหลดหลัง since รวมถึงการอัปโหลดแทนไฟล์เดิม
+func (r *dashboardRepository) NewEvidenceByEvent(userID uint, staffEvents []uint, since time.Time) ([]entities.NewEvidenceEvent, error) {
+	var events []entities.NewEvidenceEvent
+	err := r.db.Raw(`SELECT e.event_id, e.event_name,
+		COUNT(*) AS uploads,
+		MAX(v.uploaded_at) AS last_upload
+	FROM evidence_versions v
+	JOIN events e ON e.event_id = v.event_id
+	WHERE `+reviewerEvents+` AND v.uploaded_at > ?
+	GROUP BY e.event_id, e.event_name
+	ORDER BY last_upload DESC`, userID, userID, staffEvents, since).Scan(&events).Error
+	return events, err
+}
+
+// UpcomingEvents ที่นั่งทั้งหมดคือ free_space (ที่นั่งที่ยังว่าง) รวมกับผู้ที่เข้าร่วมแล้ว
+func (r *dashboardRepository) UpcomingEvents(userID uint, staffEvents []uint, from time.Time) ([]entities.EventFillRate, error) {
+	var events []entities.EventFillRate
+	err := r.db.Raw(`SELECT e.event_id, e.event_name, e.start_date,
+		e.free_space + COUNT(ei.user) AS capacity,
+		COUNT(ei.user) AS joined,
+		COALESCE(SUM(ei.status), 0) AS approved
+	FROM events e
+	LEFT JOIN event_insides ei ON ei.event_id = e.event_id
+	WHERE `+reviewerEvents+` AND e.status AND e.start_date >= ?
+	GROUP BY e.event_id, e.event_name, e.start_date, e.free_space
+	ORDER BY e.start_date`, userID, userID, staffEvents, from).Scan(&events).Error
+	return events, err
+}
+
+func (r *dashboardRepository) CountOverdue(userID uint, staffEvents []uint, before time.Time) (uint, error) {
+	var count uint
+	err := r.db.Raw(`SELECT COUNT(*) FROM (`+pendingReviews+`) p WHERE p.uploaded_at < ?`,
+		userID, userID, staffEvents, before).Scan(&count).Error
+	return count, err
+}
+
+// OverdueReviews เรียงจากที่รอนานที่สุด
+func (r *dashboardRepository) OverdueReviews(userID uint, staffEvents []uint, before time.Time, limit int) ([]entities.OverdueReview, error) {
+	var reviews []entities.OverdueReview
+	err := r.db.Raw(`SELECT e.event_id, e.event_name, p.user_id, s.code,
+		CONCAT(s.title_name, s.first_name, ' ', s.last_name) AS name,
+		p.uploaded_at
+	FROM (`+pendingReviews+`) p
+	JOIN events e ON e.event_id = p.event_id
+	JOIN students s ON s.user_id = p.user_id
+	WHERE p.uploaded_at < ?
+	ORDER BY p.uploaded_at
+	LIMIT ?`, userID, userID, staffEvents, before, limit).Scan(&reviews).Error
+	return reviews, err
+}
+
+func (r *dashboardRepository) GetLastVisit(userID uint) (*entities.DashboardVisit, error) {
+	var visit entities.DashboardVisit
+	if err := r.db.Where("user_id = ?", userID).First(&visit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &visit, nil
+}
+
+func (r *dashboardRepository) SaveVisit(visit *entities.DashboardVisit) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"visited_at"}),
+	}).Create(visit).Error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type DocumentRepository interface {
+	IssueDocument(document *entities.IssuedDocument, issue *entities.DocumentIssue) error
+	GetDocumentByCode(code string) (*entities.IssuedDocument, error)
+	GetDocumentBySource(document *entities.IssuedDocument) (*entities.IssuedDocument, error)
+	GetIssueByChecksum(documentID uint, checksum string) (*entities.DocumentIssue, error)
+}
+
+type documentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+// IssueDocument บันทึกเอกสาร (สร้างใหม่ถ้า DocumentID เป็น 0) พร้อมประวัติการออกครั้งนี้
+func (r *documentRepository) IssueDocument(document *entities.IssuedDocument, issue *entities.DocumentIssue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		save := tx.Create
+		if document.DocumentID != 0 {
+			save = tx.Omit("Owner").Save
+		}
+		if err := save(document).Error; err != nil {
+			return err
+		}
+		issue.DocumentID = document.DocumentID
+		return tx.Create(issue).Error
+	})
+}
+
+// GetDocumentBySource เอกสารล่าสุดที่ออกจากแหล่งเดียวกับ document คืน nil ถ้ายังไม่เคยออก
+func (r *documentRepository) GetDocumentBySource(document *entities.IssuedDocument) (*entities.IssuedDocument, error) {
+	var existing entities.IssuedDocument
+	err := r.db.Where("type = ? AND owner_id = ? AND event_id = ? AND school_year = ?", document.Type, document.OwnerID, document.EventID, document.SchoolYear).
+		Order("document_id DESC").First(&existing).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// GetIssueByChecksum การออกเอกสารที่ได้ไฟล์ตรงกับ checksum คืน nil ถ้าไม่เคยออกไฟล์นี้
+func (r *documentRepository) GetIssueByChecksum(documentID uint, checksum string) (*entities.DocumentIssue, error) {
+	var issue entities.DocumentIssue
+	if err := r.db.Where("document_id = ? AND checksum = ?", documentID, checksum).First(&issue).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// GetDocumentByCode คืน nil ถ้าไม่มีรหัสนี้ในทะเบียน
+func (r *documentRepository) GetDocumentByCode(code string) (*entities.IssuedDocument, error) {
+	var document entities.IssuedDocument
+	if err := r.db.Preload("Owner").Where("code = ?", code).First(&document).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &document, nil
+}
@@ -44,7 +44,7 @@ func (r *eventRepository) CreateEvent(event *entities.Event) error {
 
 func (r *eventRepository) GetAllEvent() ([]entities.Event, error) {
 	var events []entities.Event
-	if err := r.db.Preload("Teacher").Find(&events).Error; err != nil {
+	if err := r.db.Preload("Teacher").Preload("Category").Find(&events).Error; err != nil {
 		return nil, err
 	}
 	return events, nil
@@ -52,7 +52,9 @@ func (r *eventRepository) GetAllEvent() ([]entities.Event, error) {
 
 func (r *eventRepository) MyEvent(userID uint) ([]entities.Event,error){
 	var events []entities.Event
-	if err := r.db.Preload("Teacher").Where("creator = ?",userID).Find(&events).Error; err != nil {
+	if err := r.db.Preload("Teacher").Preload("Category").
+		Where("creator = ? OR event_id IN (?)", userID, r.db.Model(&entities.EventOrganizer{}).Select("event_id").Where("user_id = ?", userID)).
+		Find(&events).Error; err != nil {
 		return nil, err
 	}
 	return events, nil
@@ -60,7 +62,7 @@ func (r *eventRepository) MyEvent(userID uint) ([]entities.Event,error){
 
 func (r *eventRepository) AllAllowedEvent() ([]entities.Event, error) {
 	var events []entities.Event
-	if err := r.db.Preload("Teacher").Where("status = true").Find(&events).Error; err != nil {
+	if err := r.db.Preload("Teacher").Preload("Category").Where("status = true").Find(&events).Error; err != nil {
 		return nil, err
 	}
 	return events, nil
@@ -70,7 +72,7 @@ func (r *eventRepository) AllCurrentEvent() ([]entities.Event, error) {
 	var events []entities.Event
 	today := time.Now()
 	futureDate := today.AddDate(0, 1, 0)
-	if err := r.db.Preload("Teacher").Where("start_date BETWEEN ? AND ?", today, futureDate).Find(&events).Error; err != nil {
+	if err := r.db.Preload("Teacher").Preload("Category").Where("start_date BETWEEN ? AND ?", today, futureDate).Find(&events).Error; err != nil {
 		return nil, err
 	}
 	return events, nil
@@ -128,6 +130,9 @@ func (r *eventRepository) EditEvent(event *entities.Event) error {
 		"years":            string(yearIDsJSON),
 		"allow_all_branch": event.AllowAllBranch,
 		"allow_all_year":   event.AllowAllYear,
+		"category_id":      event.CategoryID,
+		"tags":             event.Tags,
+		"sdgs":             event.SDGs,
 	}).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to update event: %w", err)
@@ -144,7 +149,7 @@ func (r *eventRepository) GetEventByID(id uint) (*entities.Event, error) {
 	var event entities.Event
 
 	// ค้นหา Event โดยใช้ ID
-	if err := r.db.Preload("Teacher").First(&event, "event_id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Teacher").Preload("Category").First(&event, "event_id = ?", id).Error; err != nil {
 		// ตรวจสอบว่าไม่พบข้อมูล (Record Not Found)
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("event with ID %d not found", id)
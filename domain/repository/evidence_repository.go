@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EvidenceRepository interface {
+	GetVersions(eventID uint, userID uint) ([]entities.EvidenceVersion, error)
+	GetVersion(id uint) (*entities.EvidenceVersion, error)
+	GetPurgeable(before time.Time) ([]entities.EvidenceVersion, error)
+	MarkPurged(id uint) error
+}
+
+type evidenceRepository struct {
+	db *gorm.DB
+}
+
+func NewEvidenceRepository(db *gorm.DB) EvidenceRepository {
+	return &evidenceRepository{db: db}
+}
+
+// GetVersions ประวัติไฟล์หลักฐาน เรียงจากเวอร์ชันล่าสุด
+func (r *evidenceRepository) GetVersions(eventID uint, userID uint) ([]entities.EvidenceVersion, error) {
+	var versions []entities.EvidenceVersion
+	if err := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).
+		Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *evidenceRepository) GetVersion(id uint) (*entities.EvidenceVersion, error) {
+	var version entities.EvidenceVersion
+	if err := r.db.First(&version, "version_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("version with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to retrieve version: %w", err)
+	}
+	return &version, nil
+}
+
+// GetPurgeable เวอร์ชันที่ถูกแทนที่ก่อนเวลา before และยังไม่ได้ลบไฟล์
+func (r *evidenceRepository) GetPurgeable(before time.Time) ([]entities.EvidenceVersion, error) {
+	var versions []entities.EvidenceVersion
+	if err := r.db.Where("superseded_at < ? AND purged_at IS NULL", before).
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *evidenceRepository) MarkPurged(id uint) error {
+	return r.db.Model(&entities.EvidenceVersion{}).
+		Where("version_id = ?", id).
+		Update("purged_at", time.Now()).Error
+}
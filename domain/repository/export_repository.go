@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"database/sql"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ExportFilter เงื่อนไขของข้อมูลที่ส่งออก ค่า 0 คือไม่กรอง
+// BranchIDs ถ้าไม่เป็น nil จะจำกัดเฉพาะนักศึกษาในสาขาเหล่านี้ (ขอบเขตของเจ้าหน้าที่คณะ)
+type ExportFilter struct {
+	SchoolYear uint
+	FacultyID  uint
+	BranchID   uint
+	BranchIDs  []uint
+}
+
+// studentWhere เงื่อนไขของนักศึกษา ใช้กับ alias s (students) และ b (branches)
+func (f ExportFilter) studentWhere() (string, []interface{}) {
+	conds := []string{"1 = 1"}
+	var args []interface{}
+	if f.FacultyID != 0 {
+		conds = append(conds, "b.faculty_id = ?")
+		args = append(args, f.FacultyID)
+	}
+	if f.BranchID != 0 {
+		conds = append(conds, "s.branch_id = ?")
+		args = append(args, f.BranchID)
+	}
+	if f.BranchIDs != nil {
+		conds = append(conds, "s.branch_id IN ?")
+		args = append(args, f.BranchIDs)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+const exportStudentJoin = `JOIN students s ON s.user_id = %s
+	JOIN branches b ON b.branch_id = s.branch_id
+	JOIN faculties f ON f.faculty_id = b.faculty_id`
+
+// ExportRepository อ่านข้อมูลสำหรับส่งออกทีละแถว เพื่อไม่ต้องโหลดทั้งหมดไว้ในหน่วยความจำ
+type ExportRepository interface {
+	EachChecklist(eventID uint, branchIDs []uint, fn func(row entities.ChecklistExport) error) error
+	EachParticipation(filter ExportFilter, fn func(row entities.ParticipationExport) error) error
+	EachStudentHours(filter ExportFilter, fn func(row entities.StudentHoursExport) error) error
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+func joinStudent(column string) string {
+	return strings.Replace(exportStudentJoin, "%s", column, 1)
+}
+
+// each อ่านผลของ query ทีละแถวแล้วส่งให้ scan
+func (r *exportRepository) each(query *gorm.DB, scan func(rows *sql.Rows) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// EachChecklist รายชื่อผู้เข้าร่วมกิจกรรม เรียงตามสาขาแล้วตามรหัสนักศึกษา branchIDs เป็น nil คือทุกสาขา
+func (r *exportRepository) EachChecklist(eventID uint, branchIDs []uint, fn func(row entities.ChecklistExport) error) error {
+	where, args := ExportFilter{BranchIDs: branchIDs}.studentWhere()
+	query := r.db.Raw(`SELECT s.code, s.title_name, s.first_name, s.last_name, b.branch_name, f.faculty_name,
+		ei.status, ei.comment, ei.certified_at, ei.file_pdf <> '' AS has_file
+	FROM event_insides ei
+	`+joinStudent("ei.user")+`
+	WHERE ei.event_id = ? AND `+where+`
+	ORDER BY b.branch_name, s.code`, append([]interface{}{eventID}, args...)...)
+	return r.each(query, func(rows *sql.Rows) error {
+		var row entities.ChecklistExport
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		return fn(row)
+	})
+}
+
+// EachParticipation การเข้าร่วมทั้งกิจกรรมภายในและภายนอกของปีการศึกษา
+func (r *exportRepository) EachParticipation(filter ExportFilter, fn func(row entities.ParticipationExport) error) error {
+	where, args := filter.studentWhere()
+	var params []interface{}
+	params = append(params, filter.SchoolYear)
+	params = append(params, args...)
+	params = append(params, filter.SchoolYear)
+	params = append(params, args...)
+	query := r.db.Raw(`SELECT * FROM (
+		SELECT s.code, s.title_name, s.first_name, s.last_name, b.branch_name, f.faculty_name,
+			'inside' AS kind, e.event_name, COALESCE(c.name, '') AS category_name, e.start_date, e.working_hour, ei.status AS approved,
+			(NOT ei.status AND ei.certified_at IS NOT NULL) AS rejected
+		FROM event_insides ei
+		JOIN events e ON e.event_id = ei.event_id
+		LEFT JOIN categories c ON c.category_id = e.category_id
+		`+joinStudent("ei.user")+`
+		WHERE e.school_year = ? AND `+where+`
+		UNION ALL
+		SELECT s.code, s.title_name, s.first_name, s.last_name, b.branch_name, f.faculty_name,
+			'outside' AS kind, eo.event_name, COALESCE(c.name, '') AS category_name, eo.start_date, eo.working_hour, TRUE AS approved, FALSE AS rejected
+		FROM event_outsides eo
+		LEFT JOIN categories c ON c.category_id = eo.category_id
+		`+joinStudent("eo.user")+`
+		WHERE eo.school_year = ? AND `+where+`
+	) p
+	ORDER BY p.branch_name, p.code, p.start_date`, params...)
+	return r.each(query, func(rows *sql.Rows) error {
+		var row entities.ParticipationExport
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		return fn(row)
+	})
+}
+
+// EachStudentHours ชั่วโมงรวมของนักศึกษาทุกคนตามเงื่อนไข รวมคนที่ยังไม่มีกิจกรรม (ชั่วโมงเป็น 0)
+func (r *exportRepository) EachStudentHours(filter ExportFilter, fn func(row entities.StudentHoursExport) error) error {
+	where, args := filter.studentWhere()
+	params := []interface{}{filter.SchoolYear, filter.SchoolYear}
+	params = append(params, args...)
+	query := r.db.Raw(`SELECT s.user_id, s.code, s.title_name, s.first_name, s.last_name,
+		s.branch_id, b.faculty_id, s.year AS class_year, b.branch_name, f.faculty_name,
+		COALESCE(SUM(CASE WHEN p.kind = 'inside' AND p.approved THEN p.hours ELSE 0 END), 0) AS inside_hours,
+		COALESCE(SUM(CASE WHEN p.kind = 'inside' AND NOT p.approved AND NOT p.rejected THEN p.hours ELSE 0 END), 0) AS pending_hours,
+		COALESCE(SUM(CASE WHEN p.kind = 'outside' THEN p.hours ELSE 0 END), 0) AS outside_hours
+	FROM students s
+	JOIN branches b ON b.branch_id = s.branch_id
+	JOIN faculties f ON f.faculty_id = b.faculty_id
+	LEFT JOIN (
+		SELECT ei.user AS user_id, 'inside' AS kind, e.working_hour AS hours, ei.status AS approved,
+			(NOT ei.status AND ei.certified_at IS NOT NULL) AS rejected
+		FROM event_insides ei JOIN events e ON e.event_id = ei.event_id
+		WHERE e.school_year = ?
+		UNION ALL
+		SELECT eo.user AS user_id, 'outside' AS kind, eo.working_hour AS hours, TRUE AS approved, FALSE AS rejected
+		FROM event_outsides eo
+		WHERE eo.school_year = ?
+	) p ON p.user_id = s.user_id
+	WHERE `+where+`
+	GROUP BY s.user_id, s.code, s.title_name, s.first_name, s.last_name, s.branch_id, b.faculty_id, s.year, b.branch_name, f.faculty_name
+	ORDER BY b.branch_name, s.code`, params...)
+	return r.each(query, func(rows *sql.Rows) error {
+		var row entities.StudentHoursExport
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		return fn(row)
+	})
+}
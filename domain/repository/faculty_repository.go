@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FacultyRepository interface {
@@ -14,7 +15,11 @@ type FacultyRepository interface {
 	GetAllFaculties() ([]entities.Faculty, error)
 	GetFacultyByID(id uint) (*entities.Faculty, error)
 	DeleteFacultyByID(id uint) (*entities.Faculty, error)
-	AddFacultyStaff(faculty *entities.Faculty) error
+	AddFacultyStaff(staff *entities.FacultyStaff) error
+	RemoveFacultyStaff(facultyID uint, userID uint) error
+	GetFacultyStaff(facultyID uint) ([]entities.FacultyStaff, error)
+	GetFacultiesByStaff(userID uint) ([]entities.Faculty, error)
+	IsFacultyStaff(facultyID uint, userID uint) (bool, error)
 }
 
 type facultyRepository struct {
@@ -77,6 +82,47 @@ func (r *facultyRepository) DeleteFacultyByID(id uint) (*entities.Faculty, error
 	return faculty, nil
 }
 
-func (r *facultyRepository) AddFacultyStaff(faculty *entities.Faculty) error {
-	return r.db.Save(faculty).Error
+// AddFacultyStaff เพิ่มเจ้าหน้าที่ให้คณะ ถ้ามีอยู่แล้วจะไม่เพิ่มซ้ำ
+func (r *facultyRepository) AddFacultyStaff(staff *entities.FacultyStaff) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(staff).Error
+}
+
+func (r *facultyRepository) RemoveFacultyStaff(facultyID uint, userID uint) error {
+	result := r.db.Where("faculty_id = ? AND user_id = ?", facultyID, userID).Delete(&entities.FacultyStaff{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %d is not staff of faculty %d", userID, facultyID)
+	}
+	return nil
+}
+
+func (r *facultyRepository) GetFacultyStaff(facultyID uint) ([]entities.FacultyStaff, error) {
+	var staff []entities.FacultyStaff
+	if err := r.db.Preload("Teacher").Where("faculty_id = ?", facultyID).Find(&staff).Error; err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// GetFacultiesByStaff คณะทั้งหมดที่ผู้ใช้เป็นเจ้าหน้าที่
+func (r *facultyRepository) GetFacultiesByStaff(userID uint) ([]entities.Faculty, error) {
+	var faculties []entities.Faculty
+	if err := r.db.Joins("JOIN faculty_staffs ON faculty_staffs.faculty_id = faculties.faculty_id").
+		Where("faculty_staffs.user_id = ?", userID).
+		Find(&faculties).Error; err != nil {
+		return nil, err
+	}
+	return faculties, nil
+}
+
+func (r *facultyRepository) IsFacultyStaff(facultyID uint, userID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&entities.FacultyStaff{}).
+		Where("faculty_id = ? AND user_id = ?", facultyID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// FeedbackFilter เงื่อนไขค้นหาความคิดเห็นสำหรับผู้ดูแลระบบ ค่า 0 หรือ nil คือไม่กรอง
+type FeedbackFilter struct {
+	EventID    uint
+	SchoolYear uint
+	MaxRating  uint
+	Hidden     *bool
+	Offset     int
+	Limit      int
+}
+
+type FeedbackRepository interface {
+	GetFeedback(eventID uint, userID uint) (*entities.EventFeedback, error)
+	GetFeedbackByID(feedbackID uint) (*entities.EventFeedback, error)
+	SaveFeedback(feedback *entities.EventFeedback) error
+	SetHidden(feedback *entities.EventFeedback) error
+	Summaries(eventIDs []uint) ([]entities.FeedbackSummary, error)
+	VisibleComments(eventID uint) ([]entities.FeedbackComment, error)
+	ListFeedback(filter FeedbackFilter) ([]entities.FeedbackReview, int64, error)
+}
+
+type feedbackRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedbackRepository(db *gorm.DB) FeedbackRepository {
+	return &feedbackRepository{db: db}
+}
+
+func (r *feedbackRepository) GetFeedback(eventID uint, userID uint) (*entities.EventFeedback, error) {
+	var feedback entities.EventFeedback
+	if err := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&feedback).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+func (r *feedbackRepository) GetFeedbackByID(feedbackID uint) (*entities.EventFeedback, error) {
+	var feedback entities.EventFeedback
+	if err := r.db.First(&feedback, feedbackID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// SaveFeedback สร้างใหม่ถ้า FeedbackID เป็น 0 นอกนั้นแก้ไขรายการเดิม
+func (r *feedbackRepository) SaveFeedback(feedback *entities.EventFeedback) error {
+	return r.db.Omit("Event").Save(feedback).Error
+}
+
+// SetHidden บันทึกเฉพาะข้อมูลการซ่อน โดยไม่เปลี่ยน updated_at ของนักศึกษา
+func (r *feedbackRepository) SetHidden(feedback *entities.EventFeedback) error {
+	return r.db.Model(&entities.EventFeedback{}).
+		Where("feedback_id = ?", feedback.FeedbackID).
+		UpdateColumns(map[string]interface{}{
+			"hidden":        feedback.Hidden,
+			"hidden_by":     feedback.HiddenBy,
+			"hidden_at":     feedback.HiddenAt,
+			"hidden_reason": feedback.HiddenReason,
+		}).Error
+}
+
+// Summaries คะแนนรวมของแต่ละกิจกรรมใน eventIDs กิจกรรมที่ยังไม่มีคะแนนจะไม่อยู่ในผลลัพธ์
+func (r *feedbackRepository) Summaries(eventIDs []uint) ([]entities.FeedbackSummary, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		EventID                     uint
+		Count                       uint
+		Average                     float64
+		One, Two, Three, Four, Five uint
+	}
+	err := r.db.Raw(`SELECT event_id,
+		COUNT(*) AS count,
+		AVG(rating) AS average,
+		SUM(rating = 1) AS one,
+		SUM(rating = 2) AS two,
+		SUM(rating = 3) AS three,
+		SUM(rating = 4) AS four,
+		SUM(rating = 5) AS five
+	FROM event_feedbacks
+	WHERE event_id IN ?
+	GROUP BY event_id`, eventIDs).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]entities.FeedbackSummary, 0, len(rows))
+	for _, row := range rows {
+		summaries = append(summaries, entities.FeedbackSummary{
+			EventID: row.EventID,
+			Count:   row.Count,
+			Average: row.Average,
+			Stars:   [5]uint{row.One, row.Two, row.Three, row.Four, row.Five},
+		})
+	}
+	return summaries, nil
+}
+
+// VisibleComments ความคิดเห็นที่ไม่ว่างและไม่ถูกซ่อน เรียงจากล่าสุด
+func (r *feedbackRepository) VisibleComments(eventID uint) ([]entities.FeedbackComment, error) {
+	var comments []entities.FeedbackComment
+	err := r.db.Model(&entities.EventFeedback{}).
+		Select("rating, comment, created_at").
+		Where("event_id = ? AND NOT hidden AND comment <> ''", eventID).
+		Order("created_at DESC").
+		Scan(&comments).Error
+	return comments, err
+}
+
+func (r *feedbackRepository) ListFeedback(filter FeedbackFilter) ([]entities.FeedbackReview, int64, error) {
+	query := func() *gorm.DB {
+		query := r.db.Table("event_feedbacks f").
+			Joins("JOIN events e ON e.event_id = f.event_id").
+			Joins("LEFT JOIN students s ON s.user_id = f.user_id")
+		if filter.EventID != 0 {
+			query = query.Where("f.event_id = ?", filter.EventID)
+		}
+		if filter.SchoolYear != 0 {
+			query = query.Where("e.school_year = ?", filter.SchoolYear)
+		}
+		if filter.MaxRating != 0 {
+			query = query.Where("f.rating <= ?", filter.MaxRating)
+		}
+		if filter.Hidden != nil {
+			query = query.Where("f.hidden = ?", *filter.Hidden)
+		}
+		return query
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var reviews []entities.FeedbackReview
+	err := query().Select(`f.feedback_id, f.event_id, e.event_name, e.school_year, f.user_id, s.code,
+		CONCAT(s.title_name, s.first_name, ' ', s.last_name) AS name,
+		f.rating, f.comment, f.hidden, f.hidden_by, f.hidden_at, f.hidden_reason, f.created_at, f.updated_at`).
+		Order("f.created_at DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Scan(&reviews).Error
+	return reviews, total, err
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FileReferenceRepository รวบรวมทุกคอลัมน์ที่อ้างถึงไฟล์ใน storage ใช้ตรวจไฟล์กำพร้าและไฟล์ที่หายไป
+type FileReferenceRepository interface {
+	GetFileReferences() ([]entities.FileReference, error)
+	ClearFileReference(ref entities.FileReference) error
+}
+
+type fileReferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewFileReferenceRepository(db *gorm.DB) FileReferenceRepository {
+	return &fileReferenceRepository{db: db}
+}
+
+func (r *fileReferenceRepository) GetFileReferences() ([]entities.FileReference, error) {
+	var refs []entities.FileReference
+
+	var insides []entities.EventInside
+	if err := r.db.Select("event_id", "user", "file_pdf").Where("file_pdf <> ''").Find(&insides).Error; err != nil {
+		return nil, fmt.Errorf("failed to get event inside files: %w", err)
+	}
+	for _, inside := range insides {
+		refs = append(refs, entities.FileReference{Source: entities.FileRefInside, EventID: inside.EventId, UserID: inside.User, Key: inside.FilePDF})
+	}
+
+	var outsides []entities.EventOutside
+	if err := r.db.Select("event_id", "user", "file_pdf").Where("file_pdf <> ''").Find(&outsides).Error; err != nil {
+		return nil, fmt.Errorf("failed to get event outside files: %w", err)
+	}
+	for _, outside := range outsides {
+		refs = append(refs, entities.FileReference{Source: entities.FileRefOutside, EventID: outside.EventID, UserID: outside.User, Key: outside.FilePDF})
+	}
+
+	// นับเฉพาะไฟล์แนบและเวอร์ชันของการเข้าร่วมที่ยังมีอยู่ การยกเลิกเข้าร่วมหรือลบกิจกรรมทำให้ไฟล์เป็นไฟล์กำพร้า
+	var attachments []entities.Attachment
+	if err := r.db.Where(`(kind = ? AND EXISTS (SELECT 1 FROM event_insides ei WHERE ei.event_id = attachments.event_id AND ei.user = attachments.user_id))
+		OR (kind = ? AND EXISTS (SELECT 1 FROM event_outsides eo WHERE eo.event_id = attachments.event_id AND eo.user = attachments.user_id))`,
+		entities.AttachmentInside, entities.AttachmentOutside).Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	for _, attachment := range attachments {
+		refs = append(refs, entities.FileReference{Source: entities.FileRefAttachment, ID: attachment.AttachmentID, EventID: attachment.EventID, UserID: attachment.UserID, Key: attachment.FileKey})
+		if attachment.ThumbnailKey != "" {
+			refs = append(refs, entities.FileReference{Source: entities.FileRefThumbnail, ID: attachment.AttachmentID, EventID: attachment.EventID, UserID: attachment.UserID, Key: attachment.ThumbnailKey})
+		}
+	}
+
+	// เวอร์ชันที่ลบไฟล์ไปแล้วตามระยะเวลาเก็บรักษาไม่นับเป็นการอ้างอิง
+	var versions []entities.EvidenceVersion
+	if err := r.db.Where("purged_at IS NULL").
+		Where("EXISTS (SELECT 1 FROM event_insides ei WHERE ei.event_id = evidence_versions.event_id AND ei.user = evidence_versions.user_id)").
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get evidence versions: %w", err)
+	}
+	for _, version := range versions {
+		refs = append(refs, entities.FileReference{Source: entities.FileRefVersion, ID: version.VersionID, EventID: version.EventID, UserID: version.UserID, Key: version.FileKey})
+	}
+	return refs, nil
+}
+
+// ClearFileReference ลบการอ้างอิงไปยังไฟล์ที่ไม่มีอยู่จริง ให้นักศึกษาอัปโหลดใหม่ได้
+func (r *fileReferenceRepository) ClearFileReference(ref entities.FileReference) error {
+	switch ref.Source {
+	case entities.FileRefInside:
+		return r.db.Model(&entities.EventInside{}).
+			Where("event_id = ? AND user = ? AND file_pdf = ?", ref.EventID, ref.UserID, ref.Key).
+			Update("file_pdf", "").Error
+	case entities.FileRefOutside:
+		return r.db.Model(&entities.EventOutside{}).
+			Where("event_id = ? AND user = ? AND file_pdf = ?", ref.EventID, ref.UserID, ref.Key).
+			Update("file_pdf", "").Error
+	case entities.FileRefAttachment:
+		return r.db.Delete(&entities.Attachment{}, "attachment_id = ?", ref.ID).Error
+	case entities.FileRefThumbnail:
+		return r.db.Model(&entities.Attachment{}).
+			Where("attachment_id = ?", ref.ID).
+			Update("thumbnail_key", "").Error
+	case entities.FileRefVersion:
+		return r.db.Model(&entities.EvidenceVersion{}).
+			Where("version_id = ?", ref.ID).
+			Update("purged_at", time.Now()).Error
+	}
+	return fmt.Errorf("unknown file reference source %q", ref.Source)
+}
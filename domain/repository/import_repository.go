@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/transaction"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportRepository ตรวจข้อมูลซ้ำกับฐานข้อมูลและบันทึกข้อมูลจากไฟล์นำเข้า
+type ImportRepository interface {
+	ExistingEmails(emails []string) ([]string, error)
+	ExistingStudentCodes(codes []string) ([]string, error)
+	ExistingStudentPhones(phones []string) ([]string, error)
+	ExistingTeacherPhones(phones []string) ([]string, error)
+	GetTeachersByCodes(codes []string) ([]entities.User, error)
+	CreateUsers(tx transaction.Transaction, users []entities.User) error
+	UpdateTeacher(tx transaction.Transaction, user *entities.User) error
+	SaveFaculty(tx transaction.Transaction, faculty *entities.Faculty) error
+	SaveBranch(tx transaction.Transaction, branch *entities.Branch) error
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+// existing ค่าใน values ที่มีอยู่แล้วในคอลัมน์ column แบ่ง query ทีละ 1000 ค่า
+func (r *importRepository) existing(model interface{}, column string, values []string) ([]string, error) {
+	var found []string
+	for start := 0; start < len(values); start += 1000 {
+		end := start + 1000
+		if end > len(values) {
+			end = len(values)
+		}
+		var chunk []string
+		if err := r.db.Model(model).Where(column+" IN ?", values[start:end]).Pluck(column, &chunk).Error; err != nil {
+			return nil, err
+		}
+		found = append(found, chunk...)
+	}
+	return found, nil
+}
+
+func (r *importRepository) ExistingEmails(emails []string) ([]string, error) {
+	return r.existing(&entities.User{}, "email", emails)
+}
+
+func (r *importRepository) ExistingStudentCodes(codes []string) ([]string, error) {
+	return r.existing(&entities.Student{}, "code", codes)
+}
+
+func (r *importRepository) ExistingStudentPhones(phones []string) ([]string, error) {
+	return r.existing(&entities.Student{}, "phone", phones)
+}
+
+func (r *importRepository) ExistingTeacherPhones(phones []string) ([]string, error) {
+	return r.existing(&entities.Teacher{}, "phone", phones)
+}
+
+// GetTeachersByCodes user ของอาจารย์ที่มีรหัสอยู่ใน codes พร้อมข้อมูล Teacher
+func (r *importRepository) GetTeachersByCodes(codes []string) ([]entities.User, error) {
+	var users []entities.User
+	for start := 0; start < len(codes); start += 1000 {
+		end := start + 1000
+		if end > len(codes) {
+			end = len(codes)
+		}
+		var chunk []entities.User
+		err := r.db.Preload("Teacher").
+			Joins("JOIN teachers ON teachers.user_id = users.user_id").
+			Where("teachers.code IN ?", codes[start:end]).
+			Find(&chunk).Error
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, chunk...)
+	}
+	return users, nil
+}
+
+// CreateUsers บันทึก user พร้อม Student หรือ Teacher ที่แนบมา
+func (r *importRepository) CreateUsers(tx transaction.Transaction, users []entities.User) error {
+	gormTx := tx.(*transaction.GormTransaction)
+	return gormTx.GetDB().Create(&users).Error
+}
+
+// UpdateTeacher แก้ไขอีเมลของ user และข้อมูล Teacher (ไม่แก้รหัสผ่านและ role)
+func (r *importRepository) UpdateTeacher(tx transaction.Transaction, user *entities.User) error {
+	gormTx := tx.(*transaction.GormTransaction)
+	if err := gormTx.GetDB().Model(&entities.User{}).Where("user_id = ?", user.UserID).Update("email", user.Email).Error; err != nil {
+		return err
+	}
+	return gormTx.GetDB().Omit(clause.Associations).Save(user.Teacher).Error
+}
+
+// SaveFaculty สร้างคณะใหม่ถ้า FacultyID เป็น 0 นอกนั้นแก้ไขคณะเดิม
+func (r *importRepository) SaveFaculty(tx transaction.Transaction, faculty *entities.Faculty) error {
+	gormTx := tx.(*transaction.GormTransaction)
+	return gormTx.GetDB().Omit(clause.Associations).Save(faculty).Error
+}
+
+// SaveBranch สร้างสาขาใหม่ถ้า BranchID เป็น 0 นอกนั้นแก้ไขสาขาเดิม
+func (r *importRepository) SaveBranch(tx transaction.Transaction, branch *entities.Branch) error {
+	gormTx := tx.(*transaction.GormTransaction)
+	return gormTx.GetDB().Omit(clause.Associations).Save(branch).Error
+}
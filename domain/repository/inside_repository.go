@@ -5,23 +5,30 @@ import (
 	"RESTAPI/domain/transaction"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ErrEvidenceApproved หลักฐานที่ได้รับการรับรองแล้วแทนที่ไม่ได้ เพราะไฟล์ที่ถูกแทนที่จะถูกลบเมื่อหมดระยะเก็บรักษา
+var ErrEvidenceApproved = errors.New("evidence has already been approved")
+
 type EventInsideRepository interface {
 	JoinEventInside(eventInside *entities.EventInside, txManager transaction.TransactionManager) error
 	UnJoinEventInside(eventID uint, userID uint, txManager transaction.TransactionManager) error
-	UpdateEventStatusAndComment(eventID uint, userID uint, status bool, comment string) error
+	UpdateEventStatusAndComment(eventID uint, userID uint, certifierID uint, status bool, comment string) error
 	CountEventInside(eventID uint) (uint, error)
 	IsUserJoinedEvent(eventID uint, userID uint) (bool, error)
-	UpdateFile(eventID uint, userID uint, filePath string) error
+	ReplaceFile(version *entities.EvidenceVersion, txManager transaction.TransactionManager) error
 	GetFilePathByEvent(eventID uint, userID uint) (string, error)
 	GetFilePath(eventID uint, userID uint) (string, error)
 	MyChecklist(userID uint, eventID uint) ([]entities.EventInside, error)
 	AllInsideThisYears(userID uint, year uint) ([]entities.EventInside, error)
 	GroupByEvent(eventID uint) ([]uint, error)
+	GetParticipation(eventID uint, userID uint) (*entities.EventInside, error)
+	GetApprovedParticipants(eventID uint) ([]entities.EventInside, error)
+	CertifyMany(eventID uint, certifierID uint, results []entities.BulkCertifyResult, news []entities.News, txManager transaction.TransactionManager) error
 
 }
 
@@ -111,6 +118,20 @@ func (r *insideRepository) UnJoinEventInside(eventID uint, userID uint, txManage
 		return fmt.Errorf("failed to delete event: %w", err)
 	}
 
+	// ไฟล์แนบและประวัติหลักฐานของการเข้าร่วมที่ยกเลิกไม่ถูกอ้างอิงอีก ให้ storage-gc เก็บไฟล์คืน
+	if err := tx.GetDB().
+		Where("kind = ? AND event_id = ? AND user_id = ?", entities.AttachmentInside, eventID, userID).
+		Delete(&entities.Attachment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete attachments: %w", err)
+	}
+	if err := tx.GetDB().
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Delete(&entities.EvidenceVersion{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete evidence versions: %w", err)
+	}
+
 	event.FreeSpace += 1
 	if err := tx.GetDB().Save(&event).Error; err != nil {
 		tx.Rollback()
@@ -124,11 +145,95 @@ func (r *insideRepository) UnJoinEventInside(eventID uint, userID uint, txManage
 	return nil
 }
 
-func (r *insideRepository) UpdateFile(eventID uint, userID uint, filePath string) error {
-	if err := r.db.Model(&entities.EventInside{}).
-		Where("event_id = ? AND user = ?", eventID, userID).
-		Update("file_pdf", filePath).Error; err != nil {
-		return err
+// ReplaceFile บันทึกเวอร์ชันใหม่และย้าย file_pdf ไปชี้ไฟล์ใหม่ใน transaction เดียว
+// ไฟล์เดิมไม่ถูกลบ แต่ถูกทำเครื่องหมายว่าถูกแทนที่ (superseded) เพื่อเก็บเป็นประวัติ
+func (r *insideRepository) ReplaceFile(version *entities.EvidenceVersion, txManager transaction.TransactionManager) error {
+	tx := txManager.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var inside entities.EventInside
+	if err := tx.GetDB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ? AND user = ?", version.EventID, version.UserID).
+		First(&inside).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("eventID %d and userID %d not found", version.EventID, version.UserID)
+		}
+		return fmt.Errorf("failed to fetch event inside record: %w", err)
+	}
+	if inside.Status {
+		tx.Rollback()
+		return ErrEvidenceApproved
+	}
+
+	now := time.Now()
+	owner := func() *gorm.DB {
+		return tx.GetDB().Model(&entities.EvidenceVersion{}).
+			Where("event_id = ? AND user_id = ?", version.EventID, version.UserID)
+	}
+
+	var latest uint
+	if err := owner().Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to fetch latest version: %w", err)
+	}
+
+	// ไฟล์ที่อัปโหลดก่อนมีระบบเวอร์ชันจะยังไม่มีแถวประวัติ ให้บันทึกไว้ก่อนถูกแทนที่
+	if inside.FilePDF != "" {
+		var count int64
+		if err := owner().Where("file_key = ?", inside.FilePDF).Count(&count).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to check current version: %w", err)
+		}
+		if count == 0 {
+			latest++
+			legacy := &entities.EvidenceVersion{
+				EventID:    version.EventID,
+				UserID:     version.UserID,
+				Version:    latest,
+				FileKey:    inside.FilePDF,
+				UploadedAt: now,
+			}
+			if err := tx.GetDB().Create(legacy).Error; err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to record previous version: %w", err)
+			}
+		}
+	}
+
+	if err := owner().Where("superseded_at IS NULL").
+		Update("superseded_at", now).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to supersede previous version: %w", err)
+	}
+
+	// หลักฐานที่ส่งใหม่หลังถูกปฏิเสธกลับไปรอการรับรองอีกครั้ง
+	updates := map[string]interface{}{
+		"file_pdf":     version.FileKey,
+		"certified_at": nil,
+		"certified_by": nil,
+	}
+
+	version.Version = latest + 1
+	version.UploadedAt = now
+	if err := tx.GetDB().Create(version).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record new version: %w", err)
+	}
+
+	if err := tx.GetDB().Model(&entities.EventInside{}).
+		Where("event_id = ? AND user = ?", version.EventID, version.UserID).
+		Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update file path: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return nil
 }
@@ -179,10 +284,13 @@ func (r *insideRepository) MyChecklist(userID uint, eventID uint) ([]entities.Ev
 	return checklist, nil
 }
 
-func (r *insideRepository) UpdateEventStatusAndComment(eventID uint, userID uint, status bool, comment string) error {
+func (r *insideRepository) UpdateEventStatusAndComment(eventID uint, userID uint, certifierID uint, status bool, comment string) error {
 	updates := map[string]interface{}{
-		"status":  status,
-		"comment": comment,
+		"status":       status,
+		"comment":      comment,
+		"certifier":    certifierID,
+		"certified_by": certifierID,
+		"certified_at": time.Now(),
 	}
 	if err := r.db.Model(&entities.EventInside{}).
 		Where("event_id = ? AND user = ?", eventID, userID).
@@ -210,15 +318,16 @@ func (r *insideRepository) IsUserJoinedEvent(eventID uint, userID uint) (bool, e
 	return count > 0, nil
 }
 
+// AllInsideThisYears กิจกรรมภายในของผู้ใช้ในปีการศึกษา year (0 คือทุกปี) เรียงตามวันที่
 func (r *insideRepository) AllInsideThisYears(userID uint, year uint) ([]entities.EventInside, error) {
 	var eventInsides []entities.EventInside
-	// year := 2568
-	err := r.db.Preload("Event").Joins("JOIN events ON events.event_id = event_insides.event_id").
-		Where("event_insides.user = ?", userID).
-		Where("events.school_year = ?", year).
-		Find(&eventInsides).Error
-	if err != nil {
-		fmt.Println("Error fetching data:", err)
+	query := r.db.Preload("Event.Category").Joins("JOIN events ON events.event_id = event_insides.event_id").
+		Where("event_insides.user = ?", userID)
+	if year != 0 {
+		query = query.Where("events.school_year = ?", year)
+	}
+	if err := query.Order("events.school_year, events.start_date").Find(&eventInsides).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch inside events: %w", err)
 	}
 	return eventInsides, nil
 }
@@ -235,3 +344,66 @@ func (r *insideRepository) GroupByEvent(eventID uint) ([]uint, error) {
     }
     return userIDs, nil
 }
+
+// GetParticipation การเข้าร่วมหนึ่งรายการ พร้อมข้อมูลกิจกรรม ผู้สร้าง และคณะของนักศึกษา ถ้าไม่ได้เข้าร่วมคืน nil
+func (r *insideRepository) GetParticipation(eventID uint, userID uint) (*entities.EventInside, error) {
+	var inside entities.EventInside
+	if err := r.db.Preload("Event.Teacher").Preload("Student.Branch.Faculty").
+		Where("event_id = ? AND user = ?", eventID, userID).
+		First(&inside).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &inside, nil
+}
+
+// GetApprovedParticipants ผู้เข้าร่วมที่ได้รับการรับรองแล้วทั้งหมดของกิจกรรม
+func (r *insideRepository) GetApprovedParticipants(eventID uint) ([]entities.EventInside, error) {
+	var participants []entities.EventInside
+	if err := r.db.Preload("Event.Teacher").Preload("Student.Branch.Faculty").
+		Where("event_id = ? AND status = ?", eventID, true).
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+// CertifyMany บันทึกผลการรับรองของหลายคนพร้อมข่าวแจ้งนักศึกษาใน transaction เดียว
+func (r *insideRepository) CertifyMany(eventID uint, certifierID uint, results []entities.BulkCertifyResult, news []entities.News, txManager transaction.TransactionManager) error {
+	tx := txManager.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	for _, result := range results {
+		updates := map[string]interface{}{
+			"status":       result.Status,
+			"comment":      result.Comment,
+			"certifier":    certifierID,
+			"certified_by": certifierID,
+			"certified_at": now,
+		}
+		if err := tx.GetDB().Model(&entities.EventInside{}).
+			Where("event_id = ? AND user = ?", eventID, result.UserID).
+			Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update user %d: %w", result.UserID, err)
+		}
+	}
+	if len(news) > 0 {
+		if err := tx.GetDB().Create(&news).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create news: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
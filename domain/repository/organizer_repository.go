@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizerRepository interface {
+	SaveOrganizer(organizer *entities.EventOrganizer) error
+	RemoveOrganizer(eventID uint, userID uint) error
+	GetOrganizers(eventID uint) ([]entities.EventOrganizer, error)
+	GetOrganizer(eventID uint, userID uint) (*entities.EventOrganizer, error)
+}
+
+type organizerRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizerRepository(db *gorm.DB) OrganizerRepository {
+	return &organizerRepository{db: db}
+}
+
+// SaveOrganizer เพิ่มผู้จัดร่วม ถ้ามีอยู่แล้วจะอัปเดตสิทธิ์
+func (r *organizerRepository) SaveOrganizer(organizer *entities.EventOrganizer) error {
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"can_edit", "can_certify", "can_view_checklist"}),
+	}).Create(organizer).Error
+}
+
+func (r *organizerRepository) RemoveOrganizer(eventID uint, userID uint) error {
+	result := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&entities.EventOrganizer{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove organizer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %d is not an organizer of event %d", userID, eventID)
+	}
+	return nil
+}
+
+func (r *organizerRepository) GetOrganizers(eventID uint) ([]entities.EventOrganizer, error) {
+	var organizers []entities.EventOrganizer
+	if err := r.db.Preload("Teacher").Where("event_id = ?", eventID).Find(&organizers).Error; err != nil {
+		return nil, err
+	}
+	return organizers, nil
+}
+
+// GetOrganizer คืน nil ถ้าผู้ใช้ไม่ได้เป็นผู้จัดร่วมของกิจกรรมนี้
+func (r *organizerRepository) GetOrganizer(eventID uint, userID uint) (*entities.EventOrganizer, error) {
+	var organizer entities.EventOrganizer
+	if err := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).First(&organizer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &organizer, nil
+}
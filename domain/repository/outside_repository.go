@@ -12,6 +12,10 @@ type OutsideRepository interface {
 	CreateOutside(outside *entities.EventOutside) (uint,error)
 	GetOutsideByID(id uint) (*entities.EventOutside,error)
 	AllOutsideThisYears(userID uint, year uint) ([]entities.EventOutside, error) 
+	GetOutsidesByUser(userID uint) ([]entities.EventOutside, error)
+	GetOutsidesForForm(userID uint, year uint) ([]entities.EventOutside, error)
+	UpdateOutside(outside *entities.EventOutside) error
+	DeleteOutside(id uint, userID uint) error
 }
 
 type outsideRepository struct {
@@ -33,7 +37,7 @@ func (r *outsideRepository) CreateOutside(outside *entities.EventOutside) (uint,
 
 func (r *outsideRepository) GetOutsideByID(id uint) (*entities.EventOutside,error){
 	var outside entities.EventOutside
-	if err := r.db.Preload("Student.Branch.Faculty").First(&outside, "event_id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Student.Branch.Faculty").Preload("Category").First(&outside, "event_id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("event with ID %d not found", id)
 		}
@@ -42,10 +46,62 @@ func (r *outsideRepository) GetOutsideByID(id uint) (*entities.EventOutside,erro
 	return &outside, nil
 }
 
+// AllOutsideThisYears กิจกรรมภายนอกของผู้ใช้ในปีการศึกษา year (0 คือทุกปี) เรียงตามวันที่
 func (r *outsideRepository) AllOutsideThisYears(userID uint, year uint) ([]entities.EventOutside, error) {
 	var eventOutside []entities.EventOutside
-	if err := r.db.Where("user = ? AND school_year = ?", userID, year).Find(&eventOutside).Error; err != nil {
+	query := r.db.Preload("Category").Where("user = ?", userID)
+	if year != 0 {
+		query = query.Where("school_year = ?", year)
+	}
+	if err := query.Order("school_year, start_date").Find(&eventOutside).Error; err != nil {
+		return nil, err
+	}
+	return eventOutside, nil
+}
+
+// GetOutsidesForForm กิจกรรมภายนอกของปีการศึกษา พร้อมข้อมูลนักศึกษา เรียงตามวันที่สำหรับพิมพ์แบบฟอร์ม
+func (r *outsideRepository) GetOutsidesForForm(userID uint, year uint) ([]entities.EventOutside, error) {
+	var eventOutside []entities.EventOutside
+	if err := r.db.Preload("Student.Branch.Faculty").
+		Where("user = ? AND school_year = ?", userID, year).
+		Order("start_date").Find(&eventOutside).Error; err != nil {
+		return nil, err
+	}
+	return eventOutside, nil
+}
+
+func (r *outsideRepository) GetOutsidesByUser(userID uint) ([]entities.EventOutside, error) {
+	var eventOutside []entities.EventOutside
+	if err := r.db.Preload("Category").Where("user = ?", userID).Order("start_date DESC").Find(&eventOutside).Error; err != nil {
 		return nil, err
 	}
 	return eventOutside, nil
 }
+
+func (r *outsideRepository) UpdateOutside(outside *entities.EventOutside) error {
+	if err := r.db.Model(&entities.EventOutside{}).
+		Where("event_id = ? AND user = ?", outside.EventID, outside.User).
+		Updates(map[string]interface{}{
+			"event_name":   outside.EventName,
+			"school_year":  outside.SchoolYear,
+			"start_date":   outside.StartDate,
+			"intendant":    outside.Intendant,
+			"working_hour": outside.WorkingHour,
+			"location":     outside.Location,
+			"category_id":  outside.CategoryID,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	return nil
+}
+
+func (r *outsideRepository) DeleteOutside(id uint, userID uint) error {
+	result := r.db.Where("event_id = ? AND user = ?", id, userID).Delete(&entities.EventOutside{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete event with ID %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("event with ID %d not found", id)
+	}
+	return nil
+}
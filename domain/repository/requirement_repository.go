@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/transaction"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type RequirementRepository interface {
+	CreateRequirementSet(set *entities.RequirementSet) error
+	GetAllRequirementSets() ([]entities.RequirementSet, error)
+	GetRequirementSet(id uint) (*entities.RequirementSet, error)
+	GetRequirementSetByScope(facultyID, branchID, classYear *uint) (*entities.RequirementSet, error)
+	UpdateRequirementSet(set *entities.RequirementSet, txManager transaction.TransactionManager) error
+	DeleteRequirementSet(id uint) error
+	MatchingRequirementSets(facultyID, branchID, classYear uint) ([]entities.RequirementSet, error)
+	HoursByCategory(userID uint, schoolYear uint) ([]entities.CategoryHours, error)
+	StudentCategoryHours(schoolYear uint) ([]entities.StudentCategoryHours, error)
+}
+
+type requirementRepository struct {
+	db *gorm.DB
+}
+
+func NewRequirementRepository(db *gorm.DB) RequirementRepository {
+	return &requirementRepository{db: db}
+}
+
+func (r *requirementRepository) preload() *gorm.DB {
+	return r.db.Preload("Faculty").Preload("Branch").
+		Preload("Rules", func(db *gorm.DB) *gorm.DB { return db.Order("rule_id") }).
+		Preload("Rules.Category")
+}
+
+func (r *requirementRepository) CreateRequirementSet(set *entities.RequirementSet) error {
+	return r.db.Create(set).Error
+}
+
+func (r *requirementRepository) GetAllRequirementSets() ([]entities.RequirementSet, error) {
+	var sets []entities.RequirementSet
+	if err := r.preload().Order("requirement_set_id").Find(&sets).Error; err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+func (r *requirementRepository) GetRequirementSet(id uint) (*entities.RequirementSet, error) {
+	var set entities.RequirementSet
+	if err := r.preload().First(&set, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &set, nil
+}
+
+// GetRequirementSetByScope เกณฑ์ที่กำหนดกลุ่มตรงกันทุกช่อง (nil ต้องตรงกับ NULL)
+func (r *requirementRepository) GetRequirementSetByScope(facultyID, branchID, classYear *uint) (*entities.RequirementSet, error) {
+	query := r.db
+	for column, value := range map[string]*uint{"faculty_id": facultyID, "branch_id": branchID, "class_year": classYear} {
+		if value == nil {
+			query = query.Where(column + " IS NULL")
+		} else {
+			query = query.Where(column+" = ?", *value)
+		}
+	}
+	var set entities.RequirementSet
+	if err := query.First(&set).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &set, nil
+}
+
+// UpdateRequirementSet แทนที่ข้อกำหนดรายหมวดหมู่ทั้งหมดของเกณฑ์
+func (r *requirementRepository) UpdateRequirementSet(set *entities.RequirementSet, txManager transaction.TransactionManager) error {
+	tx := txManager.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.GetDB().Omit("Rules", "Faculty", "Branch").Save(set).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update requirement set: %w", err)
+	}
+	if err := tx.GetDB().Where("requirement_set_id = ?", set.RequirementSetID).Delete(&entities.RequirementRule{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete rules: %w", err)
+	}
+	for i := range set.Rules {
+		set.Rules[i].RuleID = 0
+		set.Rules[i].RequirementSetID = set.RequirementSetID
+	}
+	if len(set.Rules) > 0 {
+		if err := tx.GetDB().Omit("Category").Create(&set.Rules).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create rules: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *requirementRepository) DeleteRequirementSet(id uint) error {
+	return r.db.Delete(&entities.RequirementSet{}, id).Error
+}
+
+// MatchingRequirementSets เกณฑ์ทั้งหมดที่ใช้กับนักศึกษาของคณะ สาขา และชั้นปีที่กำหนด
+func (r *requirementRepository) MatchingRequirementSets(facultyID, branchID, classYear uint) ([]entities.RequirementSet, error) {
+	var sets []entities.RequirementSet
+	err := r.preload().
+		Where("faculty_id IS NULL OR faculty_id = ?", facultyID).
+		Where("branch_id IS NULL OR branch_id = ?", branchID).
+		Where("class_year IS NULL OR class_year = ?", classYear).
+		Order("requirement_set_id").
+		Find(&sets).Error
+	if err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// HoursByCategory ชั่วโมงของนักศึกษาแยกตามหมวดหมู่ ปีการศึกษา 0 คือทุกปี
+// กิจกรรมภายในนับเมื่อได้รับการรับรองแล้ว กิจกรรมที่ไม่ผ่านการรับรองไม่นับเป็นชั่วโมงที่รอรับรอง
+// กิจกรรมภายนอกนับตามที่บันทึก
+func (r *requirementRepository) HoursByCategory(userID uint, schoolYear uint) ([]entities.CategoryHours, error) {
+	var hours []entities.CategoryHours
+	err := r.db.Raw(`SELECT COALESCE(c.category_id, 0) AS category_id,
+		COALESCE(c.name, '') AS category_name,
+		COALESCE(SUM(CASE WHEN p.approved THEN p.hours ELSE 0 END), 0) AS earned_hours,
+		COALESCE(SUM(CASE WHEN p.approved OR p.rejected THEN 0 ELSE p.hours END), 0) AS pending_hours
+	FROM (
+		SELECT e.category_id, e.working_hour AS hours, ei.status AS approved,
+			(NOT ei.status AND ei.certified_at IS NOT NULL) AS rejected
+		FROM event_insides ei JOIN events e ON e.event_id = ei.event_id
+		WHERE ei.user = ? AND (? = 0 OR e.school_year = ?)
+		UNION ALL
+		SELECT eo.category_id, eo.working_hour AS hours, TRUE AS approved, FALSE AS rejected
+		FROM event_outsides eo
+		WHERE eo.user = ? AND (? = 0 OR eo.school_year = ?)
+	) p
+	LEFT JOIN categories c ON c.category_id = p.category_id
+	GROUP BY c.category_id, c.name
+	ORDER BY c.category_id IS NULL, c.name`, userID, schoolYear, schoolYear, userID, schoolYear, schoolYear).Scan(&hours).Error
+	return hours, err
+}
+
+// StudentCategoryHours ชั่วโมงของนักศึกษาทุกคนแยกตามหมวดหมู่ ทั้งของปีการศึกษา schoolYear และของทุกปี
+// นับแบบเดียวกับ HoursByCategory
+func (r *requirementRepository) StudentCategoryHours(schoolYear uint) ([]entities.StudentCategoryHours, error) {
+	var hours []entities.StudentCategoryHours
+	err := r.db.Raw(`SELECT p.user AS user_id, COALESCE(c.category_id, 0) AS category_id,
+		COALESCE(c.name, '') AS category_name,
+		COALESCE(SUM(CASE WHEN p.approved AND (? = 0 OR p.school_year = ?) THEN p.hours ELSE 0 END), 0) AS earned_hours,
+		COALESCE(SUM(CASE WHEN p.approved OR p.rejected OR NOT (? = 0 OR p.school_year = ?) THEN 0 ELSE p.hours END), 0) AS pending_hours,
+		COALESCE(SUM(CASE WHEN p.approved THEN p.hours ELSE 0 END), 0) AS total_earned_hours,
+		COALESCE(SUM(CASE WHEN p.approved OR p.rejected THEN 0 ELSE p.hours END), 0) AS total_pending_hours
+	FROM (
+		SELECT ei.user, e.school_year, e.category_id, e.working_hour AS hours, ei.status AS approved,
+			(NOT ei.status AND ei.certified_at IS NOT NULL) AS rejected
+		FROM event_insides ei JOIN events e ON e.event_id = ei.event_id
+		UNION ALL
+		SELECT eo.user, eo.school_year, eo.category_id, eo.working_hour AS hours, TRUE AS approved, FALSE AS rejected
+		FROM event_outsides eo
+	) p
+	LEFT JOIN categories c ON c.category_id = p.category_id
+	GROUP BY p.user, c.category_id, c.name
+	ORDER BY p.user, c.category_id IS NULL, c.name`, schoolYear, schoolYear, schoolYear, schoolYear).Scan(&hours).Error
+	return hours, err
+}
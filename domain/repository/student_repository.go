@@ -14,6 +14,7 @@ type StudentRepository interface {
 	EditStudentByID(student *entities.Student) error
 	GetAllStudent() ([]entities.Student, error)
 	GetAllStudentID() ([]uint,error)
+	GetStudentsByBranches(branchIDs []uint) ([]entities.Student, error)
 	// GetStudentByUserID(id uint) (*entities.Student, error)
 }
 
@@ -73,3 +74,14 @@ func (r *studentRepository) GetAllStudentID() ([]uint,error){
     }
     return userIDs, nil
 }
+
+func (r *studentRepository) GetStudentsByBranches(branchIDs []uint) ([]entities.Student, error) {
+	var students []entities.Student
+	if len(branchIDs) == 0 {
+		return students, nil
+	}
+	if err := r.db.Preload("Branch.Faculty").Where("branch_id IN ?", branchIDs).Find(&students).Error; err != nil {
+		return nil, err
+	}
+	return students, nil
+}
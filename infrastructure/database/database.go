@@ -55,18 +55,45 @@ func (m *mysqlDatabase) AutoMigrate() error {
 	if err := m.Db.AutoMigrate(&entities.Branch{}); err != nil {
 		return fmt.Errorf("failed to migrate Branch: %w", err)
 	}
+	if err := m.Db.AutoMigrate(&entities.FacultyStaff{}); err != nil {
+		return fmt.Errorf("failed to migrate FacultyStaff: %w", err)
+	}
 	if err := m.Db.AutoMigrate(&entities.Student{}); err != nil {
 		return fmt.Errorf("failed to migrate Student: %w", err)
 	}
+	if err := m.Db.AutoMigrate(&entities.Category{}); err != nil {
+		return fmt.Errorf("failed to migrate Category: %w", err)
+	}
 	if err := m.Db.AutoMigrate(&entities.Event{}); err != nil {
 		return fmt.Errorf("failed to migrate Event: %w", err)
 	}
 	if err := m.Db.AutoMigrate(&entities.EventInside{}); err != nil {
 		return fmt.Errorf("failed to migrate EventInside: %w", err)
 	}
+	if err := m.Db.AutoMigrate(&entities.EventOrganizer{}); err != nil {
+		return fmt.Errorf("failed to migrate EventOrganizer: %w", err)
+	}
 	if err := m.Db.AutoMigrate(&entities.EventOutside{}); err != nil {
 		return fmt.Errorf("failed to migrate EventInside: %w", err)
 	}
+	if err := m.Db.AutoMigrate(&entities.Attachment{}); err != nil {
+		return fmt.Errorf("failed to migrate Attachment: %w", err)
+	}
+	if err := m.Db.AutoMigrate(&entities.EvidenceVersion{}); err != nil {
+		return fmt.Errorf("failed to migrate EvidenceVersion: %w", err)
+	}
+	if err := m.Db.AutoMigrate(&entities.RequirementSet{}, &entities.RequirementRule{}); err != nil {
+		return fmt.Errorf("failed to migrate RequirementSet: %w", err)
+	}
+	if err := m.Db.AutoMigrate(&entities.EventFeedback{}); err != nil {
+		return fmt.Errorf("failed to migrate EventFeedback: %w", err)
+	}
+	if err := m.Db.AutoMigrate(&entities.DashboardVisit{}); err != nil {
+		return fmt.Errorf("failed to migrate DashboardVisit: %w", err)
+	}
+	if err := m.Db.AutoMigrate(&entities.IssuedDocument{}, &entities.DocumentIssue{}); err != nil {
+		return fmt.Errorf("failed to migrate IssuedDocument: %w", err)
+	}
 
 	if err := m.Db.AutoMigrate(&entities.Done{}); err != nil {
 		return fmt.Errorf("failed to migrate EventInside: %w", err)
@@ -116,6 +143,21 @@ func SetupDatabase(cfg *config.Config) Database {
             END IF;
         END;
     `)
+	// ย้าย super_user เดิมของแต่ละคณะไปเป็นเจ้าหน้าที่คณะ
+	if err := db.GetDb().Exec(`
+        INSERT IGNORE INTO faculty_staffs (faculty_id, user_id)
+        SELECT faculty_id, super_user FROM faculties WHERE super_user IS NOT NULL
+    `).Error; err != nil {
+		log.Printf("Failed to migrate faculty super users: %v", err)
+	}
+	// certifier เดิมถูกตั้งเป็นผู้สร้างกิจกรรมตั้งแต่ตอนเข้าร่วม ให้ตรงกับผู้ที่รับรองจริง
+	if err := db.GetDb().Exec(`
+        UPDATE event_insides SET certifier = certified_by
+        WHERE certified_by IS NOT NULL AND (certifier IS NULL OR certifier <> certified_by)
+    `).Error; err != nil {
+		log.Printf("Failed to migrate event inside certifiers: %v", err)
+	}
+
     password, err := pkg.HashPassword(cfg.Admin.Password)
     if err != nil {
         log.Fatalf("failed to hash password: %v", err)
@@ -1,20 +1,27 @@
 package jwt
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"strings"
 	"time"
 	"github.com/golang-jwt/jwt/v5"
 	"RESTAPI/config"
 )
 
 type JWTService struct {
-	SecretKey string
+	SecretKey  string
+	FileURLTTL time.Duration
 }
 
 // NewJWTService สร้าง Service สำหรับจัดการ JWT
 func NewJWTService(cfg *config.Config) *JWTService {
 	return &JWTService{
-		SecretKey: cfg.JWTSecret,
+		SecretKey:  cfg.JWTSecret,
+		FileURLTTL: cfg.FileURLTTL,
 	}
 }
 
@@ -53,3 +60,91 @@ func (j *JWTService) ValidateJWT(tokenString string) (jwt.MapClaims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// fileKey ใช้ key แยกจาก token สำหรับ login เพื่อไม่ให้ token ของไฟล์ใช้แทน cookie ได้
+func (j *JWTService) fileKey() []byte {
+	return []byte(j.SecretKey + ":file")
+}
+
+// GenerateFileToken สร้าง token อายุสั้นสำหรับดาวน์โหลดไฟล์หลักฐานของ (eventID, ownerID)
+// fileKey ผูก token ไว้กับไฟล์ที่มีอยู่ตอนสร้างลิงก์ ถ้านักศึกษาอัปโหลดไฟล์ใหม่ลิงก์เดิมจะใช้ไม่ได้
+func (j *JWTService) GenerateFileToken(eventID uint, ownerID uint, fileKey string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(j.FileURLTTL)
+	claims := jwt.MapClaims{
+		"event_id": eventID,
+		"owner_id": ownerID,
+		"file_key": fileKey,
+		"exp":      expiresAt.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(j.fileKey())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ValidateFileToken ตรวจสอบ token ของไฟล์และคืนค่า eventID, ownerID, fileKey
+func (j *JWTService) ValidateFileToken(tokenString string) (uint, uint, string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return j.fileKey(), nil
+	})
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return 0, 0, "", errors.New("invalid token")
+	}
+	eventID, ok := claims["event_id"].(float64)
+	if !ok {
+		return 0, 0, "", errors.New("invalid event_id in token")
+	}
+	ownerID, ok := claims["owner_id"].(float64)
+	if !ok {
+		return 0, 0, "", errors.New("invalid owner_id in token")
+	}
+	fileKey, ok := claims["file_key"].(string)
+	if !ok || fileKey == "" {
+		return 0, 0, "", errors.New("invalid file_key in token")
+	}
+	return uint(eventID), uint(ownerID), fileKey, nil
+}
+
+// documentKey key สำหรับเซ็นรหัสเอกสารที่พิมพ์ลง QR code แยกจาก key ของ login และไฟล์
+func (j *JWTService) documentKey() []byte {
+	return []byte(j.SecretKey + ":document")
+}
+
+func (j *JWTService) documentSignature(id string) string {
+	mac := hmac.New(sha256.New, j.documentKey())
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:12])
+}
+
+// GenerateDocumentCode สร้างรหัสเอกสารแบบสุ่มพร้อมลายเซ็น รูปแบบ "<id>.<signature>"
+// ใช้ลายเซ็นแบบสั้นแทน JWT เพื่อให้ QR code มีขนาดเล็ก
+func (j *JWTService) GenerateDocumentCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := base64.RawURLEncoding.EncodeToString(buf)
+	return id + "." + j.documentSignature(id), nil
+}
+
+// ValidateDocumentCode ตรวจลายเซ็นของรหัสเอกสาร รหัสที่ปลอมขึ้นจะถูกปฏิเสธโดยไม่ต้องค้นฐานข้อมูล
+func (j *JWTService) ValidateDocumentCode(code string) error {
+	id, signature, ok := strings.Cut(code, ".")
+	if !ok || id == "" {
+		return errors.New("invalid document code")
+	}
+	if !hmac.Equal([]byte(signature), []byte(j.documentSignature(id))) {
+		return errors.New("invalid document code")
+	}
+	return nil
+}
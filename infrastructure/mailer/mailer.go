@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured ใช้เมื่อยังไม่ได้ตั้งค่า SMTP ไว้
+var ErrNotConfigured = errors.New("email is not configured")
+
+// Mailer ส่งอีเมลข้อความธรรมดา (UTF-8)
+type Mailer interface {
+	Enabled() bool
+	Send(to string, subject string, body string) error
+}
+
+type noopMailer struct{}
+
+// NewNoopMailer ใช้เมื่อไม่ได้ตั้งค่า SMTP ไว้ Send คืน ErrNotConfigured เสมอ
+func NewNoopMailer() Mailer {
+	return noopMailer{}
+}
+
+func (noopMailer) Enabled() bool {
+	return false
+}
+
+func (noopMailer) Send(to string, subject string, body string) error {
+	return ErrNotConfigured
+}
+
+type smtpMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer ส่งผ่าน SMTP (STARTTLS ถ้าเซิร์ฟเวอร์รองรับ) ถ้า username ว่างจะไม่ยืนยันตัวตน
+func NewSMTPMailer(host string, port int, username string, password string, from string) Mailer {
+	return &smtpMailer{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (m *smtpMailer) Enabled() bool {
+	return true
+}
+
+func (m *smtpMailer) Send(to string, subject string, body string) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	var msg strings.Builder
+	msg.WriteString("From: " + m.from + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.BEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	if err := smtp.SendMail(m.addr, auth, m.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to, err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"RESTAPI/infrastructure/jwt"
+	"strings"
 
 
 	"github.com/gofiber/fiber/v2"
@@ -62,3 +63,24 @@ func RoleMiddleware(allowedRoles ...string) fiber.Handler {
 
 
 
+
+// BodyLimit จำกัดขนาด body ของ request เป็น limit ยกเว้นเส้นทางอัปโหลดใน uploadPaths ซึ่งรับได้ถึง uploadLimit
+// uploadPaths เป็น prefix ของ path เช่น "/protected/student/upload" ครอบคลุม "/protected/student/upload/:id"
+func BodyLimit(limit int, uploadLimit int, uploadPaths ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		max := limit
+		path := strings.ToLower(ctx.Path())
+		for _, prefix := range uploadPaths {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				max = uploadLimit
+				break
+			}
+		}
+		if ctx.Request().Header.ContentLength() > max || len(ctx.Request().Body()) > max {
+			return ctx.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "Request body is too large",
+			})
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"RESTAPI/config"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage เก็บไฟล์บน S3 หรือบริการที่รองรับ S3 API เช่น MinIO
+func NewS3Storage(cfg config.Storage) (Storage, error) {
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, cfg.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.S3Bucket, minio.MakeBucketOptions{Region: cfg.S3Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket: %w", err)
+		}
+	}
+	return &s3Storage{client: client, bucket: cfg.S3Bucket}, nil
+}
+
+// key ตัด prefix "./uploads/" ของค่าเก่าออก ให้ตรงกับ key ของ local storage
+func (s *s3Storage) key(key string) string {
+	return NormalizeKey(key)
+}
+
+func (s *s3Storage) Save(key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(context.Background(), s.bucket, s.key(key), r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+func (s *s3Storage) Open(key string) (io.ReadCloser, error) {
+	ctx := context.Background()
+	if _, err := s.client.StatObject(ctx, s.bucket, s.key(key), minio.StatObjectOptions{}); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s.client.GetObject(ctx, s.bucket, s.key(key), minio.GetObjectOptions{})
+}
+
+func (s *s3Storage) Delete(key string) error {
+	return s.client.RemoveObject(context.Background(), s.bucket, s.key(key), minio.RemoveObjectOptions{})
+}
+
+func (s *s3Storage) Exists(key string) (bool, error) {
+	_, err := s.client.StatObject(context.Background(), s.bucket, s.key(key), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *s3Storage) List(prefix string) ([]Object, error) {
+	var objects []Object
+	for object := range s.client.ListObjects(context.Background(), s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, Object{Key: object.Key, Size: object.Size, ModTime: object.LastModified})
+	}
+	return objects, nil
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"RESTAPI/config"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNotFound ใช้เมื่อไม่พบไฟล์ตาม key ที่ระบุ
+var ErrNotFound = errors.New("file not found in storage")
+
+// Storage ที่เก็บไฟล์หลักฐาน key เป็น path แบบสัมพัทธ์ เช่น "12/<uuid>.pdf"
+type Storage interface {
+	Save(key string, r io.Reader, size int64, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	Exists(key string) (bool, error)
+	List(prefix string) ([]Object, error)
+}
+
+// Object ข้อมูลไฟล์ที่ได้จาก List
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// NormalizeKey แปลง key แบบเดิม ("./uploads/12/x.pdf") ให้อยู่ในรูปเดียวกับที่ List คืน ("12/x.pdf")
+func NormalizeKey(key string) string {
+	key = strings.TrimPrefix(key, "./")
+	return strings.TrimPrefix(key, "uploads/")
+}
+
+// NewStorage เลือก backend ตาม STORAGE_DRIVER (local หรือ s3)
+func NewStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.Storage.LocalDir), nil
+	case "s3":
+		return NewS3Storage(cfg.Storage)
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyticsController สถิติรวมสำหรับผู้ดูแลระบบ ทุกเส้นทางรับ query school_year (ไม่ระบุคือทุกปี)
+type AnalyticsController struct {
+	usecase usecase.AnalyticsUsecase
+}
+
+func NewAnalyticsController(usecase usecase.AnalyticsUsecase) *AnalyticsController {
+	return &AnalyticsController{usecase: usecase}
+}
+
+func schoolYearQuery(ctx *fiber.Ctx) (uint, bool) {
+	if ctx.Query("school_year") == "" {
+		return 0, true
+	}
+	year, err := strconv.Atoi(ctx.Query("school_year"))
+	if err != nil || year <= 0 {
+		return 0, false
+	}
+	return uint(year), true
+}
+
+func (c *AnalyticsController) respond(ctx *fiber.Ctx, result interface{}, err error) error {
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(result)
+}
+
+func invalidSchoolYear(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "Invalid school_year",
+	})
+}
+
+// Participation จำนวนการเข้าร่วมและชั่วโมง query group_by=faculty|branch|year|school_year
+func (c *AnalyticsController) Participation(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	stats, err := c.usecase.Participation(year, ctx.Query("group_by"))
+	return c.respond(ctx, stats, err)
+}
+
+// FillRates อัตราการเข้าร่วมของแต่ละกิจกรรม
+func (c *AnalyticsController) FillRates(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	rates, err := c.usecase.FillRates(year)
+	return c.respond(ctx, rates, err)
+}
+
+// TopOrganizers อาจารย์ที่จัดกิจกรรมมากที่สุด query limit (ค่าเริ่มต้น 10)
+func (c *AnalyticsController) TopOrganizers(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	stats, err := c.usecase.TopOrganizers(year, ctx.QueryInt("limit", 10))
+	return c.respond(ctx, stats, err)
+}
+
+// Turnaround ระยะเวลาตั้งแต่ส่งหลักฐานจนได้รับการรับรอง
+func (c *AnalyticsController) Turnaround(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	report, err := c.usecase.Turnaround(year)
+	return c.respond(ctx, report, err)
+}
+
+// Requirement สัดส่วนนักศึกษาที่มีชั่วโมงครบตามที่กำหนด (ต้องระบุ school_year)
+func (c *AnalyticsController) Requirement(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	report, err := c.usecase.Requirement(year)
+	return c.respond(ctx, report, err)
+}
+
+// Categories การเข้าร่วมและชั่วโมงแยกตามหมวดหมู่กิจกรรม
+func (c *AnalyticsController) Categories(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	stats, err := c.usecase.Categories(year)
+	return c.respond(ctx, stats, err)
+}
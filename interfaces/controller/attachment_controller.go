@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+	"RESTAPI/utility/fileSystem"
+	"errors"
+	"mime"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AttachmentController ไฟล์แนบหลักฐาน (:kind คือ inside หรือ outside)
+type AttachmentController struct {
+	usecase usecase.AttachmentUsecase
+}
+
+func NewAttachmentController(usecase usecase.AttachmentUsecase) *AttachmentController {
+	return &AttachmentController{usecase: usecase}
+}
+
+func (c *AttachmentController) UploadAttachment(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "failed to get file",
+		})
+	}
+	attachment, err := c.usecase.UploadAttachment(ctx.Params("kind"), eventID, userID, file)
+	if err != nil {
+		var validationErr *filesystem.ValidationError
+		if errors.As(err, &validationErr) {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": validationErr.Message,
+				"code":  validationErr.Code,
+			})
+		}
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusCreated).JSON(attachment)
+}
+
+// GetAttachments รายการไฟล์แนบของ :userid ในกิจกรรม :id
+func (c *AttachmentController) GetAttachments(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	ownerID, err := strconv.Atoi(ctx.Params("userid"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid UserID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	attachments, err := c.usecase.GetAttachments(ctx.Params("kind"), eventID, uint(ownerID), userID, role)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(attachments)
+}
+
+func (c *AttachmentController) GetAttachment(ctx *fiber.Ctx) error {
+	return c.sendAttachment(ctx, false)
+}
+
+func (c *AttachmentController) GetThumbnail(ctx *fiber.Ctx) error {
+	return c.sendAttachment(ctx, true)
+}
+
+func (c *AttachmentController) sendAttachment(ctx *fiber.Ctx, thumbnail bool) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid attachment ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	file, attachment, err := c.usecase.OpenAttachment(id, userID, role, thumbnail)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if thumbnail {
+		ctx.Set("Content-Type", "image/jpeg")
+	} else {
+		ctx.Set("Content-Type", attachment.ContentType)
+		ctx.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	}
+	return ctx.SendStream(file)
+}
+
+func (c *AttachmentController) DeleteAttachment(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid attachment ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	if err := c.usecase.DeleteAttachment(id, userID); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Attachment deleted successfully",
+	})
+}
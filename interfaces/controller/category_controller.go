@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryController หมวดหมู่ของกิจกรรม
+type CategoryController struct {
+	usecase usecase.CategoryUsecase
+}
+
+func NewCategoryController(usecase usecase.CategoryUsecase) *CategoryController {
+	return &CategoryController{usecase: usecase}
+}
+
+func (c *CategoryController) respond(ctx *fiber.Ctx, status int, result interface{}, err error) error {
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(status).JSON(result)
+}
+
+func (c *CategoryController) AddCategory(ctx *fiber.Ctx) error {
+	var req usecase.CategoryRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	category, err := c.usecase.CreateCategory(&req)
+	return c.respond(ctx, fiber.StatusCreated, category, err)
+}
+
+func (c *CategoryController) GetAllCategories(ctx *fiber.Ctx) error {
+	categories, err := c.usecase.GetAllCategories()
+	return c.respond(ctx, fiber.StatusOK, categories, err)
+}
+
+func (c *CategoryController) GetCategory(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+	category, err := c.usecase.GetCategory(id)
+	return c.respond(ctx, fiber.StatusOK, category, err)
+}
+
+func (c *CategoryController) UpdateCategory(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+	var req usecase.CategoryRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	category, err := c.usecase.UpdateCategory(id, &req)
+	return c.respond(ctx, fiber.StatusOK, category, err)
+}
+
+func (c *CategoryController) DeleteCategory(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+	if err := c.usecase.DeleteCategory(id); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Category deleted successfully",
+	})
+}
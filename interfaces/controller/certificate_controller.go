@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+	"mime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CertificateController เกียรติบัตรการเข้าร่วมกิจกรรมภายใน
+type CertificateController struct {
+	usecase usecase.CertificateUsecase
+}
+
+func NewCertificateController(usecase usecase.CertificateUsecase) *CertificateController {
+	return &CertificateController{usecase: usecase}
+}
+
+// MyCertificate เกียรติบัตรของนักศึกษาในกิจกรรม :id
+func (c *CertificateController) MyCertificate(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	data, fileName, err := c.usecase.MyCertificate(eventID, userID)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx.Set("Content-Type", "application/pdf")
+	ctx.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return ctx.Send(data)
+}
+
+// EventCertificates เกียรติบัตรทั้งหมดของกิจกรรม :id เป็นไฟล์ ZIP
+func (c *CertificateController) EventCertificates(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	data, fileName, err := c.usecase.EventCertificates(eventID, userID, role)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx.Set("Content-Type", "application/zip")
+	ctx.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return ctx.Send(data)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DashboardController สรุปงานตรวจสำหรับอาจารย์
+type DashboardController struct {
+	usecase usecase.DashboardUsecase
+}
+
+func NewDashboardController(usecase usecase.DashboardUsecase) *DashboardController {
+	return &DashboardController{usecase: usecase}
+}
+
+// TeacherDashboard query mark_seen=false ใช้ดึงจำนวนสำหรับ badge โดยไม่บันทึกว่าเข้าชมแล้ว
+func (c *DashboardController) TeacherDashboard(ctx *fiber.Ctx) error {
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	dashboard, err := c.usecase.TeacherDashboard(userID, ctx.QueryBool("mark_seen", true))
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(dashboard)
+}
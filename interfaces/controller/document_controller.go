@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DocumentController ตรวจสอบเอกสารที่ระบบออก (สาธารณะ ไม่ต้อง login)
+type DocumentController struct {
+	usecase usecase.DocumentUsecase
+}
+
+func NewDocumentController(usecase usecase.DocumentUsecase) *DocumentController {
+	return &DocumentController{usecase: usecase}
+}
+
+// Verify ตรวจรหัสจาก QR code ถ้า POST ไฟล์ PDF มาใน field "file" จะตรวจว่าไฟล์ไม่ถูกแก้ไขด้วย
+func (c *DocumentController) Verify(ctx *fiber.Ctx) error {
+	var data []byte
+	if ctx.Method() == fiber.MethodPost {
+		file, err := ctx.FormFile("file")
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "failed to get file",
+			})
+		}
+		src, err := file.Open()
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "failed to open file",
+			})
+		}
+		data, err = io.ReadAll(src)
+		src.Close()
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "failed to read file",
+			})
+		}
+	}
+	result, err := c.usecase.Verify(ctx.Params("code"), data)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if !result.Valid {
+		return ctx.Status(fiber.StatusNotFound).JSON(result)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(result)
+}
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// statusFromError แปลง error จาก usecase เป็น HTTP status
+func statusFromError(err error) int {
+	if errors.Is(err, usecase.ErrPermissionDenied) {
+		return fiber.StatusForbidden
+	}
+	if errors.Is(err, usecase.ErrInvalidImport) || errors.Is(err, usecase.ErrInvalidRequest) {
+		return fiber.StatusBadRequest
+	}
+	if errors.Is(err, usecase.ErrNotFound) {
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
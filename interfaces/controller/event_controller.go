@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"RESTAPI/domain/entities"
 	"RESTAPI/domain/transaction"
 	"RESTAPI/usecase"
 	"strconv"
@@ -56,14 +57,43 @@ func (c *EventController) CreateEvent(ctx *fiber.Ctx) error {
 		"message": "Create successfully",
 	})
 }
+// eventFilter อ่าน query category_id, tag และ sdg สำหรับกรองรายการกิจกรรม
+func eventFilter(ctx *fiber.Ctx) (usecase.EventFilter, bool) {
+	filter := usecase.EventFilter{Tag: ctx.Query("tag")}
+	for key, target := range map[string]*uint{"category_id": &filter.CategoryID, "sdg": &filter.SDG} {
+		if ctx.Query(key) == "" {
+			continue
+		}
+		id, err := strconv.Atoi(ctx.Query(key))
+		if err != nil || id <= 0 {
+			return usecase.EventFilter{}, false
+		}
+		*target = uint(id)
+	}
+	if filter.SDG > entities.MaxSDG {
+		return usecase.EventFilter{}, false
+	}
+	return filter, true
+}
+
+func invalidEventFilter(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "Invalid category_id or sdg",
+	})
+}
+
 func (c *EventController) GetAllEvent(ctx *fiber.Ctx) error {
+	filter, ok := eventFilter(ctx)
+	if !ok {
+		return invalidEventFilter(ctx)
+	}
 	events, err := c.usecase.GetAllEvent()
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Unable to retrieve events",
 		})
 	}
-	return ctx.Status(fiber.StatusOK).JSON(events)
+	return ctx.Status(fiber.StatusOK).JSON(usecase.FilterEvents(events, filter))
 }
 
 
@@ -82,32 +112,44 @@ func (c *EventController) MyEvent(ctx *fiber.Ctx) error {
 		})
 	}
 	userID := uint(userIDFloat)
+	filter, ok := eventFilter(ctx)
+	if !ok {
+		return invalidEventFilter(ctx)
+	}
 	events, err := c.usecase.MyEvent(userID)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Unable to retrieve events",
 		})
 	}
-	return ctx.Status(fiber.StatusOK).JSON(events)
+	return ctx.Status(fiber.StatusOK).JSON(usecase.FilterEvents(events, filter))
 }
 
 func (c *EventController) AllAllowedEvent(ctx *fiber.Ctx) error {
+	filter, ok := eventFilter(ctx)
+	if !ok {
+		return invalidEventFilter(ctx)
+	}
 	events, err := c.usecase.AllAllowedEvent()
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Unable to retrieve events",
 		})
 	}
-	return ctx.Status(fiber.StatusOK).JSON(events)
+	return ctx.Status(fiber.StatusOK).JSON(usecase.FilterEvents(events, filter))
 }
 func (c *EventController) AllCurrentEvent(ctx *fiber.Ctx) error {
+	filter, ok := eventFilter(ctx)
+	if !ok {
+		return invalidEventFilter(ctx)
+	}
 	events, err := c.usecase.AllCurrentEvent()
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Unable to retrieve events",
 		})
 	}
-	return ctx.Status(fiber.StatusOK).JSON(events)
+	return ctx.Status(fiber.StatusOK).JSON(usecase.FilterEvents(events, filter))
 }
 func (c *EventController) GetEventByID(ctx *fiber.Ctx) error {
 	id, err := utility.GetUintID(ctx)
@@ -261,3 +303,108 @@ func (c *EventController) AllMyEventThisYear(ctx *fiber.Ctx) error {
 		"outside_events": outsideEvents,
 	})
 }
+
+
+func (c *EventController) SaveOrganizer(ctx *fiber.Ctx) error {
+	var req usecase.OrganizerRequest
+
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	if err := c.usecase.SaveOrganizer(id, userID, &req); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Organizer saved successfully",
+	})
+}
+
+func (c *EventController) RemoveOrganizer(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	organizerID, err := strconv.Atoi(ctx.Params("userid"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid UserID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+
+	if err := c.usecase.RemoveOrganizer(id, userID, uint(organizerID)); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Organizer removed successfully",
+	})
+}
+
+func (c *EventController) GetOrganizers(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+
+	organizers, err := c.usecase.GetOrganizers(id, userID)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(organizers)
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"RESTAPI/domain/repository"
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+	"RESTAPI/utility/export"
+	"bufio"
+	"log"
+	"mime"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportController ส่งออกรายชื่อและชั่วโมงกิจกรรมเป็นไฟล์ CSV หรือ XLSX (?format=csv|xlsx)
+type ExportController struct {
+	usecase usecase.ExportUsecase
+}
+
+func NewExportController(usecase usecase.ExportUsecase) *ExportController {
+	return &ExportController{usecase: usecase}
+}
+
+// claims ผู้ใช้และ role จาก JWT
+func (c *ExportController) claims(ctx *fiber.Ctx) (uint, string, bool) {
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return 0, "", false
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return 0, "", false
+	}
+	role, _ := claims["role"].(string)
+	return userID, role, true
+}
+
+// filter อ่าน :year และ query faculty_id, branch_id
+func (c *ExportController) filter(ctx *fiber.Ctx) (repository.ExportFilter, bool) {
+	var filter repository.ExportFilter
+	year, err := strconv.Atoi(ctx.Params("year"))
+	if err != nil || year <= 0 {
+		return filter, false
+	}
+	filter.SchoolYear = uint(year)
+	for key, target := range map[string]*uint{"faculty_id": &filter.FacultyID, "branch_id": &filter.BranchID} {
+		if ctx.Query(key) == "" {
+			continue
+		}
+		id, err := strconv.Atoi(ctx.Query(key))
+		if err != nil || id <= 0 {
+			return filter, false
+		}
+		*target = uint(id)
+	}
+	return filter, true
+}
+
+// send ส่งไฟล์แบบ stream ข้อผิดพลาดระหว่างเขียนส่ง status ไม่ได้แล้ว จึงบันทึก log ไว้
+func (c *ExportController) send(ctx *fiber.Ctx, format string, write usecase.ExportFunc, fileName string, err error) error {
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx.Set("Content-Type", export.ContentType(format))
+	ctx.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(w); err != nil {
+			log.Printf("export %s failed: %v", fileName, err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+func (c *ExportController) exportChecklist(ctx *fiber.Ctx, staff bool) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	format, err := export.ValidFormat(ctx.Query("format"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	userID, role, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	write, fileName, err := c.usecase.ExportChecklist(eventID, userID, role, staff, format)
+	return c.send(ctx, format, write, fileName, err)
+}
+
+// ExportChecklist รายชื่อผู้เข้าร่วมกิจกรรม :id สำหรับผู้สร้าง ผู้จัดร่วม และ admin
+func (c *ExportController) ExportChecklist(ctx *fiber.Ctx) error {
+	return c.exportChecklist(ctx, false)
+}
+
+// StaffExportChecklist รายชื่อผู้เข้าร่วมกิจกรรม :id เฉพาะนักศึกษาในคณะของเจ้าหน้าที่
+func (c *ExportController) StaffExportChecklist(ctx *fiber.Ctx) error {
+	return c.exportChecklist(ctx, true)
+}
+
+// ExportParticipations การเข้าร่วมกิจกรรมทั้งหมดในปีการศึกษา :year
+func (c *ExportController) ExportParticipations(ctx *fiber.Ctx) error {
+	filter, ok := c.filter(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid year, faculty_id or branch_id",
+		})
+	}
+	format, err := export.ValidFormat(ctx.Query("format"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	userID, role, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	write, fileName, err := c.usecase.ExportParticipations(filter, userID, role, format)
+	return c.send(ctx, format, write, fileName, err)
+}
+
+// ExportStudentHours ชั่วโมงรวมรายคนในปีการศึกษา :year
+func (c *ExportController) ExportStudentHours(ctx *fiber.Ctx) error {
+	filter, ok := c.filter(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid year, faculty_id or branch_id",
+		})
+	}
+	format, err := export.ValidFormat(ctx.Query("format"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	userID, role, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	write, fileName, err := c.usecase.ExportStudentHours(filter, userID, role, format)
+	return c.send(ctx, format, write, fileName, err)
+}
@@ -140,10 +140,50 @@ func (c *FacultyController) AddFacultyStaff(ctx *fiber.Ctx) error{
 
     if err:=c.usecase.AddFacultyStaff(facultyID,userID);err != nil {
         return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-            "error":err,
+            "error":err.Error(),
         })
     }
     return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
         "massage":"successfully",
     })
-}
\ No newline at end of file
+}
+
+func (c *FacultyController) RemoveFacultyStaff(ctx *fiber.Ctx) error{
+    facultyID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid faculty ID",
+		})
+	}
+	idInt, err := strconv.Atoi(ctx.Params("userid"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid UserID",
+		})
+	}
+
+    if err:=c.usecase.RemoveFacultyStaff(facultyID,uint(idInt));err != nil {
+        return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+            "error":err.Error(),
+        })
+    }
+    return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+        "message":"Staff removed successfully",
+    })
+}
+
+func (c *FacultyController) GetFacultyStaff(ctx *fiber.Ctx) error{
+    facultyID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid faculty ID",
+		})
+	}
+    staff,err := c.usecase.GetFacultyStaff(facultyID)
+    if err != nil {
+        return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+            "error":err.Error(),
+        })
+    }
+    return ctx.Status(fiber.StatusOK).JSON(staff)
+}
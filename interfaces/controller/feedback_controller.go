@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"RESTAPI/domain/repository"
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeedbackController คะแนนและความคิดเห็นต่อกิจกรรม
+type FeedbackController struct {
+	usecase usecase.FeedbackUsecase
+}
+
+func NewFeedbackController(usecase usecase.FeedbackUsecase) *FeedbackController {
+	return &FeedbackController{usecase: usecase}
+}
+
+func (c *FeedbackController) claims(ctx *fiber.Ctx) (uint, string, bool) {
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return 0, "", false
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return 0, "", false
+	}
+	role, _ := claims["role"].(string)
+	return userID, role, true
+}
+
+func (c *FeedbackController) respond(ctx *fiber.Ctx, result interface{}, err error) error {
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(result)
+}
+
+// SubmitFeedback ส่งหรือแก้ไขคะแนนและความคิดเห็นต่อกิจกรรม :id
+func (c *FeedbackController) SubmitFeedback(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	userID, _, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	var req usecase.FeedbackRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	feedback, err := c.usecase.SubmitFeedback(eventID, userID, &req)
+	return c.respond(ctx, feedback, err)
+}
+
+// MyFeedback ความคิดเห็นของนักศึกษาเองต่อกิจกรรม :id
+func (c *FeedbackController) MyFeedback(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	userID, _, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	feedback, err := c.usecase.MyFeedback(eventID, userID)
+	return c.respond(ctx, feedback, err)
+}
+
+// EventFeedback คะแนนรวมและความคิดเห็นของกิจกรรม :id สำหรับผู้จัด
+func (c *FeedbackController) EventFeedback(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	userID, role, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	report, err := c.usecase.EventFeedback(eventID, userID, role)
+	return c.respond(ctx, report, err)
+}
+
+// ListFeedback ความคิดเห็นทุกกิจกรรมสำหรับผู้ดูแลระบบ
+// query event_id, school_year, max_rating, hidden=true|false, page (เริ่มที่ 1) และ limit (ค่าเริ่มต้น 20)
+func (c *FeedbackController) ListFeedback(ctx *fiber.Ctx) error {
+	filter := repository.FeedbackFilter{
+		EventID:    uint(ctx.QueryInt("event_id", 0)),
+		SchoolYear: uint(ctx.QueryInt("school_year", 0)),
+		MaxRating:  uint(ctx.QueryInt("max_rating", 0)),
+		Limit:      ctx.QueryInt("limit", 20),
+	}
+	if ctx.QueryInt("event_id", 0) < 0 || ctx.QueryInt("school_year", 0) < 0 || ctx.QueryInt("max_rating", 0) < 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event_id, school_year or max_rating",
+		})
+	}
+	if v := ctx.Query("hidden"); v != "" {
+		hidden, err := strconv.ParseBool(v)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid hidden",
+			})
+		}
+		filter.Hidden = &hidden
+	}
+	page, err := c.usecase.ListFeedback(filter, ctx.QueryInt("page", 1))
+	return c.respond(ctx, page, err)
+}
+
+// ModerateFeedback ซ่อนหรือเลิกซ่อนความคิดเห็น :id
+func (c *FeedbackController) ModerateFeedback(ctx *fiber.Ctx) error {
+	feedbackID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid feedback ID",
+		})
+	}
+	adminID, _, ok := c.claims(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	var req usecase.ModerationRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	feedback, err := c.usecase.ModerateFeedback(feedbackID, adminID, &req)
+	return c.respond(ctx, feedback, err)
+}
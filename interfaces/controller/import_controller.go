@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/usecase"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportController นำเข้าข้อมูลจำนวนมากจากไฟล์ CSV/XLSX (form field "file")
+// query: dry_run=true ตรวจอย่างเดียว, mode=atomic|chunk, send_credentials=true ส่งรหัสผ่านทางอีเมล
+type ImportController struct {
+	usecase usecase.ImportUsecase
+}
+
+func NewImportController(usecase usecase.ImportUsecase) *ImportController {
+	return &ImportController{usecase: usecase}
+}
+
+func importOptions(ctx *fiber.Ctx) usecase.ImportOptions {
+	return usecase.ImportOptions{
+		DryRun:          ctx.QueryBool("dry_run"),
+		Mode:            ctx.Query("mode"),
+		SendCredentials: ctx.QueryBool("send_credentials"),
+	}
+}
+
+type importFunc func(fileName string, file io.Reader, options usecase.ImportOptions) (*entities.ImportReport, error)
+
+// runImport อ่านไฟล์จาก form แล้วส่งให้ importFn ตอบกลับรายงานผลรายแถว
+func runImport(ctx *fiber.Ctx, importFn importFunc) error {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "failed to get file",
+		})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "failed to open file",
+		})
+	}
+	defer file.Close()
+
+	report, err := importFn(fileHeader.Filename, file, importOptions(ctx))
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	status := fiber.StatusOK
+	if report.Created > 0 {
+		status = fiber.StatusCreated
+	}
+	return ctx.Status(status).JSON(report)
+}
+
+// ImportStudents นำเข้านักศึกษา
+func (c *ImportController) ImportStudents(ctx *fiber.Ctx) error {
+	return runImport(ctx, c.usecase.ImportStudents)
+}
+
+// ImportStructure นำเข้าโครงสร้างคณะและสาขา
+func (c *ImportController) ImportStructure(ctx *fiber.Ctx) error {
+	return runImport(ctx, c.usecase.ImportStructure)
+}
+
+// ImportTeachers นำเข้ารายชื่ออาจารย์
+func (c *ImportController) ImportTeachers(ctx *fiber.Ctx) error {
+	return runImport(ctx, c.usecase.ImportTeachers)
+}
@@ -1,8 +1,14 @@
 package controller
 
 import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/infrastructure/jwt"
 	"RESTAPI/usecase"
 	"RESTAPI/utility"
+	"RESTAPI/utility/fileSystem"
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,15 +18,18 @@ type EventInsideController struct {
 	insideUsecase usecase.EventInsideUsecase
 	eventUsecase  usecase.EventUsecase
 	userUsecase   usecase.UserUsecase
+	jwtService    jwt.JWTService
 }
 
 func NewEventInsideController(insideUsecase usecase.EventInsideUsecase,
 	eventUsecase usecase.EventUsecase,
-	userUsecase usecase.UserUsecase) *EventInsideController {
+	userUsecase usecase.UserUsecase,
+	jwtService jwt.JWTService) *EventInsideController {
 	return &EventInsideController{
 		insideUsecase: insideUsecase,
 		eventUsecase:  eventUsecase,
 		userUsecase:   userUsecase,
+		jwtService:    jwtService,
 	}
 }
 
@@ -119,7 +128,14 @@ func (c *EventInsideController) UploadFile(ctx *fiber.Ctx) error {
 	}
 
 	if err := c.insideUsecase.UploadFile(file, id, userID); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		var validationErr *filesystem.ValidationError
+		if errors.As(err, &validationErr) {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": validationErr.Message,
+				"code":  validationErr.Code,
+			})
+		}
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -152,14 +168,20 @@ func (c *EventInsideController) GetFileForMe(ctx *fiber.Ctx) error {
 		})
 	}
 	userID := uint(userIDFloat)
-	// ดึง URL ของไฟล์ที่ต้องการ
-	filePath, err := c.insideUsecase.GetFile(id, userID)
+	// เปิดไฟล์ของตัวเองจาก storage
+	file, err := c.insideUsecase.GetFile(id, userID)
 	if err != nil {
 		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
-	return ctx.SendFile(filePath)
+	return sendPDF(ctx, file)
+}
+
+// sendPDF ส่งไฟล์แบบ stream โดย fiber จะปิด reader ให้เมื่อส่งเสร็จ
+func sendPDF(ctx *fiber.Ctx, file io.ReadCloser) error {
+	ctx.Set("Content-Type", "application/pdf")
+	return ctx.SendStream(file)
 }
 
 func (c *EventInsideController) GetFile(ctx *fiber.Ctx) error {
@@ -177,16 +199,175 @@ func (c *EventInsideController) GetFile(ctx *fiber.Ctx) error {
 		})
 	}
 	userID := uint(idInt)
-	filePath, err := c.insideUsecase.GetFile(eventID, userID)
+	claims, err := utility.GetClaimsFromContext(ctx)
 	if err != nil {
-		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	requesterID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	file, err := c.insideUsecase.GetFileFor(eventID, userID, requesterID, role)
+	if err != nil {
+		status := fiber.StatusNotFound
+		if statusFromError(err) == fiber.StatusForbidden {
+			status = fiber.StatusForbidden
+		}
+		return ctx.Status(status).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
-	return ctx.SendFile(filePath, false)
+	return sendPDF(ctx, file)
 
 }
 
+// GetFileVersions ประวัติไฟล์หลักฐานของ :userid ในกิจกรรม :id
+func (c *EventInsideController) GetFileVersions(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	idInt, err := strconv.Atoi(ctx.Params("userid"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid UserID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	requesterID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	versions, err := c.insideUsecase.GetFileVersions(eventID, uint(idInt), requesterID, role)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(versions)
+}
+
+func (c *EventInsideController) GetFileVersion(ctx *fiber.Ctx) error {
+	versionID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid version ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	requesterID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	file, err := c.insideUsecase.GetFileVersion(versionID, requesterID, role)
+	if err != nil {
+		status := fiber.StatusNotFound
+		if statusFromError(err) == fiber.StatusForbidden {
+			status = fiber.StatusForbidden
+		}
+		return ctx.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return sendPDF(ctx, file)
+}
+
+// CreateFileLink สร้างลิงก์ดาวน์โหลดอายุสั้นสำหรับส่งให้ frontend โดยไม่ต้องแนบ cookie
+func (c *EventInsideController) CreateFileLink(ctx *fiber.Ctx) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	idInt, err := strconv.Atoi(ctx.Params("userid"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid UserID",
+		})
+	}
+	ownerID := uint(idInt)
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	requesterID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	if err := c.insideUsecase.CanViewFile(eventID, ownerID, requesterID, role); err != nil {
+		status := fiber.StatusNotFound
+		if statusFromError(err) == fiber.StatusForbidden {
+			status = fiber.StatusForbidden
+		}
+		return ctx.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	fileKey, err := c.insideUsecase.FileKey(eventID, ownerID)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	token, expiresAt, err := c.jwtService.GenerateFileToken(eventID, ownerID, fileKey)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate link",
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"url":        fmt.Sprintf("/files/%s", token),
+		"expires_at": expiresAt,
+	})
+}
+
+// GetSignedFile ดาวน์โหลดไฟล์ผ่านลิงก์ที่สร้างจาก CreateFileLink
+func (c *EventInsideController) GetSignedFile(ctx *fiber.Ctx) error {
+	eventID, ownerID, fileKey, err := c.jwtService.ValidateFileToken(ctx.Params("token"))
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired link",
+		})
+	}
+	file, err := c.insideUsecase.GetFileByKey(eventID, ownerID, fileKey)
+	if err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return sendPDF(ctx, file)
+}
+
 func (c *EventInsideController) MyChecklist(ctx *fiber.Ctx) error {
 	id, err := utility.GetUintID(ctx)
 	if err != nil {
@@ -207,13 +388,48 @@ func (c *EventInsideController) MyChecklist(ctx *fiber.Ctx) error {
 		})
 	}
 	userID := uint(userIDFloat)
-	checklist, err := c.insideUsecase.MyChecklist(userID, id)
+	role, _ := claims["role"].(string)
+	checklist, err := c.insideUsecase.MyChecklist(userID, role, id)
 	if err != nil {
-		return err
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 	return ctx.Status(fiber.StatusOK).JSON(checklist)
 }
 
+// AttendanceSheet ใบลงชื่อเข้าร่วมกิจกรรม :id สำหรับพิมพ์
+func (c *EventInsideController) AttendanceSheet(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	data, fileName, err := c.insideUsecase.AttendanceSheet(userID, role, id)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx.Set("Content-Type", "application/pdf")
+	ctx.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	return ctx.Send(data)
+}
+
 func (c *EventInsideController) ConfirmAndCheck(ctx *fiber.Ctx) error {
 	var req struct {
 		Status  bool   `json:"status"`
@@ -234,14 +450,28 @@ func (c *EventInsideController) ConfirmAndCheck(ctx *fiber.Ctx) error {
 	}
 	userID := uint(idInt)
 
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	certifierID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+
 	if err := ctx.BodyParser(&req); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request payload",
 		})
 	}
 
-	if err := c.insideUsecase.UpdateEventStatusAndComment(eventID, userID, req.Status, req.Comment); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	if err := c.insideUsecase.UpdateEventStatusAndComment(eventID, userID, certifierID, role, req.Status, req.Comment); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
@@ -251,6 +481,53 @@ func (c *EventInsideController) ConfirmAndCheck(ctx *fiber.Ctx) error {
 	})
 }
 
+// BulkConfirmAndCheck รับรองหรือตีกลับผู้เข้าร่วมหลายคนของกิจกรรม :id ในครั้งเดียว
+func (c *EventInsideController) BulkConfirmAndCheck(ctx *fiber.Ctx) error {
+	return c.bulkConfirmAndCheck(ctx, false)
+}
+
+// StaffBulkConfirmAndCheck รับรองแบบกลุ่มในฐานะเจ้าหน้าที่คณะ เฉพาะนักศึกษาในคณะของตน
+func (c *EventInsideController) StaffBulkConfirmAndCheck(ctx *fiber.Ctx) error {
+	return c.bulkConfirmAndCheck(ctx, true)
+}
+
+func (c *EventInsideController) bulkConfirmAndCheck(ctx *fiber.Ctx, staff bool) error {
+	eventID, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Failed to get user claims",
+		})
+	}
+	certifierID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+
+	var req entities.BulkCertifyRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	report, err := c.insideUsecase.BulkCertify(eventID, certifierID, role, staff, &req)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(report)
+}
+
 func (c *EventInsideController) CountEventInside(ctx *fiber.Ctx) error {
 	id, err := utility.GetUintID(ctx)
 	if err != nil {
@@ -4,7 +4,9 @@ import (
 	"RESTAPI/domain/entities"
 	"RESTAPI/usecase"
 	"RESTAPI/utility"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -68,8 +70,27 @@ func (c *OutsideController) GetOutsideByID(ctx *fiber.Ctx) error{
 			"error":"Invalid event ID",
 		})
 	}
-	outside,err:= c.usecase.GetOutsideByID(id)
+	claims, err := utility.GetClaimsFromContext(ctx)
 	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+
+	outside,err:= c.usecase.GetOutsideByID(id, userID, role)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPermissionDenied) {
+			return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":"Failed to retrieve event",
 		})
@@ -85,10 +106,24 @@ func (c *OutsideController) DownloadPDF(ctx *fiber.Ctx) error{
 		})
 	}
 
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+
 	// เรียกใช้ usecase เพื่อสร้าง PDF ในหน่วยความจำ
-	data, fileName, err := c.usecase.CreateFile(id)
+	data, fileName, err := c.usecase.CreateFile(id, userID, role)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).SendString(fmt.Sprintf("Error: %v", err))
+		return ctx.Status(statusFromError(err)).SendString(fmt.Sprintf("Error: %v", err))
 	}
 
 	// กำหนดค่า HTTP headers เพื่อให้ผู้ใช้ดาวน์โหลดไฟล์
@@ -96,4 +131,133 @@ func (c *OutsideController) DownloadPDF(ctx *fiber.Ctx) error{
 	ctx.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
 
 	return ctx.Send(data)
-}
\ No newline at end of file
+}
+
+// DownloadYearPDF แบบบันทึกรวมทั้งปีการศึกษา ถ้าไม่มี :id จะเป็นของผู้ใช้เอง
+func (c *OutsideController) DownloadYearPDF(ctx *fiber.Ctx) error {
+	year, err := strconv.Atoi(ctx.Params("year"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid year",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	ownerID := userID
+	if ctx.Params("id") != "" {
+		ownerID, err = utility.GetUintID(ctx)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid student ID",
+			})
+		}
+	}
+
+	data, fileName, err := c.usecase.CreateYearFile(ownerID, uint(year), userID, role)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx.Set("Content-Type", "application/pdf")
+	ctx.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	return ctx.Send(data)
+}
+
+func (c *OutsideController) MyOutside(ctx *fiber.Ctx) error {
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	outside, err := c.usecase.MyOutside(userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Unable to retrieve events",
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(outside)
+}
+
+func (c *OutsideController) EditOutside(ctx *fiber.Ctx) error {
+	var req entities.OutsideRequest
+
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	if err := c.usecase.EditOutside(id, req, userID); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Update event success",
+	})
+}
+
+func (c *OutsideController) DeleteOutside(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	if err := c.usecase.DeleteOutside(id, userID); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Event deleted successfully",
+	})
+}
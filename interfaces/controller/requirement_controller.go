@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirementController เกณฑ์ชั่วโมงกิจกรรมและผลการประเมินของนักศึกษา
+type RequirementController struct {
+	usecase usecase.RequirementUsecase
+}
+
+func NewRequirementController(usecase usecase.RequirementUsecase) *RequirementController {
+	return &RequirementController{usecase: usecase}
+}
+
+func (c *RequirementController) respond(ctx *fiber.Ctx, status int, result interface{}, err error) error {
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(status).JSON(result)
+}
+
+func (c *RequirementController) AddRequirementSet(ctx *fiber.Ctx) error {
+	var req usecase.RequirementSetRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	set, err := c.usecase.CreateRequirementSet(&req)
+	return c.respond(ctx, fiber.StatusCreated, set, err)
+}
+
+func (c *RequirementController) GetAllRequirementSets(ctx *fiber.Ctx) error {
+	sets, err := c.usecase.GetAllRequirementSets()
+	return c.respond(ctx, fiber.StatusOK, sets, err)
+}
+
+func (c *RequirementController) GetRequirementSet(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid requirement set ID",
+		})
+	}
+	set, err := c.usecase.GetRequirementSet(id)
+	return c.respond(ctx, fiber.StatusOK, set, err)
+}
+
+func (c *RequirementController) UpdateRequirementSet(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid requirement set ID",
+		})
+	}
+	var req usecase.RequirementSetRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+	set, err := c.usecase.UpdateRequirementSet(id, &req)
+	return c.respond(ctx, fiber.StatusOK, set, err)
+}
+
+func (c *RequirementController) DeleteRequirementSet(ctx *fiber.Ctx) error {
+	id, err := utility.GetUintID(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid requirement set ID",
+		})
+	}
+	if err := c.usecase.DeleteRequirementSet(id); err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Requirement set deleted successfully",
+	})
+}
+
+// Evaluate ผลการประเมินชั่วโมงตามเกณฑ์ query school_year (ไม่ระบุคือตลอดหลักสูตร)
+// ถ้ามี :id คือนักศึกษาที่เจ้าหน้าที่ขอดู
+func (c *RequirementController) Evaluate(ctx *fiber.Ctx) error {
+	year, ok := schoolYearQuery(ctx)
+	if !ok {
+		return invalidSchoolYear(ctx)
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	studentID := userID
+	if ctx.Params("id") != "" {
+		studentID, err = utility.GetUintID(ctx)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid student ID",
+			})
+		}
+	}
+	evaluation, err := c.usecase.Evaluate(studentID, year, userID, role)
+	return c.respond(ctx, fiber.StatusOK, evaluation, err)
+}
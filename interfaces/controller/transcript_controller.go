@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"RESTAPI/usecase"
+	"RESTAPI/utility"
+	"mime"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TranscriptController ใบสรุปชั่วโมงกิจกรรมของนักศึกษา
+type TranscriptController struct {
+	usecase usecase.TranscriptUsecase
+}
+
+func NewTranscriptController(usecase usecase.TranscriptUsecase) *TranscriptController {
+	return &TranscriptController{usecase: usecase}
+}
+
+// DownloadTranscript ไม่ระบุ :year คือตลอดหลักสูตร ถ้ามี :id คือนักศึกษาที่เจ้าหน้าที่ขอดู
+func (c *TranscriptController) DownloadTranscript(ctx *fiber.Ctx) error {
+	var year uint
+	if ctx.Params("year") != "" {
+		yearInt, err := strconv.Atoi(ctx.Params("year"))
+		if err != nil || yearInt <= 0 {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid year",
+			})
+		}
+		year = uint(yearInt)
+	}
+	claims, err := utility.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid JWT claims",
+		})
+	}
+	userID, ok := utility.GetUserIDFromClaims(claims)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid user_id in claims",
+		})
+	}
+	role, _ := claims["role"].(string)
+	ownerID := userID
+	if ctx.Params("id") != "" {
+		ownerID, err = utility.GetUintID(ctx)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid student ID",
+			})
+		}
+	}
+
+	data, fileName, err := c.usecase.Transcript(ownerID, year, userID, role)
+	if err != nil {
+		return ctx.Status(statusFromError(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx.Set("Content-Type", "application/pdf")
+	ctx.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return ctx.Send(data)
+}
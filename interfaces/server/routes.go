@@ -1,11 +1,15 @@
 package server
 
 import (
+	"RESTAPI/config"
 	"RESTAPI/domain/repository"
 	"RESTAPI/domain/transaction"
 	"RESTAPI/infrastructure/database"
 	"RESTAPI/infrastructure/jwt"
+	"RESTAPI/infrastructure/mailer"
 	"RESTAPI/infrastructure/middleware"
+	"RESTAPI/infrastructure/storage"
+	"RESTAPI/utility/fileSystem"
 	"RESTAPI/interfaces/controller"
 	"RESTAPI/usecase"
 	"fmt"
@@ -13,8 +17,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// uploadBodyLimit ขนาด body สูงสุดของเส้นทางอัปโหลด เผื่อขนาดสำหรับ multipart header นอกเหนือจากตัวไฟล์
+func uploadBodyLimit(cfg *config.Config) int {
+	return int(cfg.Upload.MaxSize) + 1<<20
+}
+
 // SetupRoutes ฟังก์ชันสำหรับกำหนดเส้นทางทั้งหมด
-func SetupRoutes(app *fiber.App, db database.Database, jwtService *jwt.JWTService) {
+func SetupRoutes(app *fiber.App, cfg *config.Config, db database.Database, jwtService *jwt.JWTService, store storage.Storage, validator *filesystem.PDFValidator, renderer *filesystem.PDFRenderer, mail mailer.Mailer) {
 	txManager := transaction.NewGormTransactionManager(db.GetDb())
 	userRepo := repository.NewUserRepository(db.GetDb())
 	studentRepo := repository.NewStudentRepository(db.GetDb())
@@ -31,20 +40,78 @@ func SetupRoutes(app *fiber.App, db database.Database, jwtService *jwt.JWTServic
 	branchController := controller.NewBranchController(branchUsecase)
 
 	eventRepo := repository.NewEventRepository(db.GetDb())
+	organizerRepo := repository.NewOrganizerRepository(db.GetDb())
 
 	insideRepo := repository.NewEventInsideRepository(db.GetDb())
 	outsideRepo := repository.NewOutsideRepository(db.GetDb())
-	eventUsecase := usecase.NewEventUsecase(eventRepo, branchRepo, insideRepo,outsideRepo,studentRepo)
-	insideUsecase := usecase.NewEventInsideUsecase(insideRepo, userRepo, eventUsecase, txManager)
+	feedbackRepo := repository.NewFeedbackRepository(db.GetDb())
+	categoryRepo := repository.NewCategoryRepository(db.GetDb())
+	eventUsecase := usecase.NewEventUsecase(eventRepo, branchRepo, insideRepo,outsideRepo,studentRepo,organizerRepo,userRepo,feedbackRepo,categoryRepo)
+	staffUsecase := usecase.NewStaffUsecase(facultyRepo, branchRepo, userRepo, studentRepo, insideRepo, organizerRepo, eventUsecase)
+	staffController := controller.NewStaffController(staffUsecase)
+
+	evidenceRepo := repository.NewEvidenceRepository(db.GetDb())
+	insideUsecase := usecase.NewEventInsideUsecase(insideRepo, evidenceRepo, userRepo, eventUsecase, staffUsecase, txManager, store, validator, renderer)
 	eventController := controller.NewEventController(eventUsecase, txManager)
-	insideController := controller.NewEventInsideController(insideUsecase, eventUsecase, userUsecase)
+	insideController := controller.NewEventInsideController(insideUsecase, eventUsecase, userUsecase, *jwtService)
 
-	outsideUsecase := usecase.NewOutsideUsecase(outsideRepo)
+	documentRepo := repository.NewDocumentRepository(db.GetDb())
+	documentUsecase := usecase.NewDocumentUsecase(documentRepo, jwtService, renderer, cfg.PublicBaseURL)
+	documentController := controller.NewDocumentController(documentUsecase)
+
+	attachmentRepo := repository.NewAttachmentRepository(db.GetDb())
+	outsideUsecase := usecase.NewOutsideUsecase(outsideRepo, attachmentRepo, staffUsecase, store, renderer, documentUsecase, categoryRepo)
 	outsideController := controller.NewOutsideController(outsideUsecase)
 
+	attachmentUsecase := usecase.NewAttachmentUsecase(attachmentRepo, insideRepo, insideUsecase, outsideUsecase, store, validator, cfg.Upload.MaxAttachments)
+	attachmentController := controller.NewAttachmentController(attachmentUsecase)
+
+	certificateUsecase := usecase.NewCertificateUsecase(insideRepo, organizerRepo, userRepo, eventUsecase, renderer, documentUsecase)
+	certificateController := controller.NewCertificateController(certificateUsecase)
+
+	requirementRepo := repository.NewRequirementRepository(db.GetDb())
+	requirementUsecase := usecase.NewRequirementUsecase(requirementRepo, categoryRepo, facultyRepo, branchRepo, userRepo, staffUsecase, txManager, cfg.RequiredHoursPerYear)
+	requirementController := controller.NewRequirementController(requirementUsecase)
+
+	transcriptUsecase := usecase.NewTranscriptUsecase(userRepo, eventUsecase, staffUsecase, documentUsecase, requirementUsecase, renderer)
+	transcriptController := controller.NewTranscriptController(transcriptUsecase)
+
+	exportRepo := repository.NewExportRepository(db.GetDb())
+	exportUsecase := usecase.NewExportUsecase(exportRepo, eventUsecase, staffUsecase, requirementUsecase)
+	exportController := controller.NewExportController(exportUsecase)
+
+	importRepo := repository.NewImportRepository(db.GetDb())
+	importUsecase := usecase.NewImportUsecase(importRepo, facultyRepo, branchRepo, txManager, mail, cfg.PublicBaseURL)
+	importController := controller.NewImportController(importUsecase)
+
+	analyticsRepo := repository.NewAnalyticsRepository(db.GetDb())
+	analyticsUsecase := usecase.NewAnalyticsUsecase(analyticsRepo, requirementUsecase, cfg.AnalyticsCacheTTL)
+	analyticsController := controller.NewAnalyticsController(analyticsUsecase)
+
+	feedbackUsecase := usecase.NewFeedbackUsecase(feedbackRepo, insideRepo, eventUsecase, cfg.FeedbackEditWindow)
+	feedbackController := controller.NewFeedbackController(feedbackUsecase)
+
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo)
+	categoryController := controller.NewCategoryController(categoryUsecase)
+
+	dashboardRepo := repository.NewDashboardRepository(db.GetDb())
+	dashboardUsecase := usecase.NewDashboardUsecase(dashboardRepo, staffUsecase, cfg.ReviewOverdueDays)
+	dashboardController := controller.NewDashboardController(dashboardUsecase)
+
+	// เฉพาะเส้นทางที่รับไฟล์เท่านั้นที่รับ body ได้ใหญ่กว่าขนาดปกติของ fiber
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, uploadBodyLimit(cfg),
+		"/verify",
+		"/protected/student/upload",
+		"/protected/student/attachment",
+		"/protected/admin/import",
+	))
+
 	app.Post("/register/student", userController.RegisterStudent)
 	app.Post("/register/teacher", userController.RegisterTeacher)
 	app.Post("/login", userController.Login)
+	app.Get("/files/:token", insideController.GetSignedFile)
+	app.Get("/verify/:code", documentController.Verify)
+	app.Post("/verify/:code", documentController.Verify)
 	app.Get("/hello", func(c *fiber.Ctx) error {
 		fmt.Println("hello")
 		return c.SendString("Hello, world!")
@@ -54,11 +121,14 @@ func SetupRoutes(app *fiber.App, db database.Database, jwtService *jwt.JWTServic
 	super:=protected.Group("/super",middleware.RoleMiddleware("superadmin"))
 	teacher := protected.Group("/teacher", middleware.RoleMiddleware("teacher"))
 	student := protected.Group("/student", middleware.RoleMiddleware("student"))
+	staff := protected.Group("/staff", middleware.RoleMiddleware("teacher", "admin"))
 	
 	super.Put("/role/:id",userController.EditRole)
 	admin.Put("/role/:id",userController.EditRole)
 	
 	admin.Put("/staff/:id/:userid",facultyController.AddFacultyStaff)
+	admin.Delete("/staff/:id/:userid",facultyController.RemoveFacultyStaff)
+	admin.Get("/staff/:id",facultyController.GetFacultyStaff)
 	admin.Post("/event", eventController.CreateEvent)
 	teacher.Post("/event", eventController.CreateEvent)
 	
@@ -80,9 +150,29 @@ func SetupRoutes(app *fiber.App, db database.Database, jwtService *jwt.JWTServic
 	app.Get("/branchbyfaculty/:id", branchController.GetBranchesByFaculty)
 	admin.Put("/branch/:id", branchController.UpdateBranch)
 	admin.Delete("/branch/:id", branchController.DeleteBranchByID)
+	admin.Post("/category", categoryController.AddCategory)
+	app.Get("/categories", categoryController.GetAllCategories)
+	app.Get("/category/:id", categoryController.GetCategory)
+	admin.Put("/category/:id", categoryController.UpdateCategory)
+	admin.Delete("/category/:id", categoryController.DeleteCategory)
+	admin.Post("/requirement", requirementController.AddRequirementSet)
+	admin.Get("/requirements", requirementController.GetAllRequirementSets)
+	admin.Get("/requirement/:id", requirementController.GetRequirementSet)
+	admin.Put("/requirement/:id", requirementController.UpdateRequirementSet)
+	admin.Delete("/requirement/:id", requirementController.DeleteRequirementSet)
 
 	admin.Get("/students", userController.GetAllStudent)
 	admin.Get("/teachers", userController.GetAllTeacher)
+	admin.Post("/import/students", importController.ImportStudents)
+	admin.Post("/import/structure", importController.ImportStructure)
+	admin.Post("/import/teachers", importController.ImportTeachers)
+
+	admin.Get("/analytics/participation", analyticsController.Participation)
+	admin.Get("/analytics/fillrates", analyticsController.FillRates)
+	admin.Get("/analytics/organizers", analyticsController.TopOrganizers)
+	admin.Get("/analytics/turnaround", analyticsController.Turnaround)
+	admin.Get("/analytics/requirement", analyticsController.Requirement)
+	admin.Get("/analytics/categories", analyticsController.Categories)
 
 	admin.Put("/status/:id", eventController.StatusEvent)
 	teacher.Put("/status/:id", eventController.StatusEvent)
@@ -91,10 +181,19 @@ func SetupRoutes(app *fiber.App, db database.Database, jwtService *jwt.JWTServic
 	app.Get("/currentevents", eventController.AllCurrentEvent)
 	teacher.Get("/myevents",eventController.MyEvent)
 	admin.Get("/myevents",eventController.MyEvent)
+	teacher.Get("/dashboard", dashboardController.TeacherDashboard)
+	admin.Get("/dashboard", dashboardController.TeacherDashboard)
 	app.Get("/event/:id", eventController.GetEventByID)
 	admin.Put("/event/:id", eventController.EditEvent)
 	teacher.Put("/event/:id", eventController.EditEvent)
 
+	teacher.Get("/event/:id/organizers", eventController.GetOrganizers)
+	admin.Get("/event/:id/organizers", eventController.GetOrganizers)
+	teacher.Put("/event/:id/organizer", eventController.SaveOrganizer)
+	admin.Put("/event/:id/organizer", eventController.SaveOrganizer)
+	teacher.Delete("/event/:id/organizer/:userid", eventController.RemoveOrganizer)
+	admin.Delete("/event/:id/organizer/:userid", eventController.RemoveOrganizer)
+
 	// admin.Delete("/event/:id", eventController.DeleteEvent)
 	teacher.Delete("/event/:id", eventController.DeleteEvent)
 	admin.Delete("/event/:id", eventController.DeleteEvent)
@@ -109,16 +208,68 @@ func SetupRoutes(app *fiber.App, db database.Database, jwtService *jwt.JWTServic
 	student.Get("/file/:id", insideController.GetFileForMe)
 	teacher.Get("/file/:id/:userid", insideController.GetFile)
 	admin.Get("/file/:id/:userid", insideController.GetFile)
+	staff.Get("/file/:id/:userid", insideController.GetFile)
+	protected.Get("/filelink/:id/:userid", insideController.CreateFileLink)
+	protected.Get("/fileversions/:id/:userid", insideController.GetFileVersions)
+	protected.Get("/fileversion/:id", insideController.GetFileVersion)
 	teacher.Put("/check/:id/:userid", insideController.ConfirmAndCheck)
 	admin.Put("/check/:id/:userid", insideController.ConfirmAndCheck)
+	teacher.Put("/check/:id", insideController.BulkConfirmAndCheck)
+	admin.Put("/check/:id", insideController.BulkConfirmAndCheck)
 	teacher.Get("/checklist/:id",insideController.MyChecklist)
 	admin.Get("/checklist/:id",insideController.MyChecklist)
+	teacher.Get("/attendance/:id", insideController.AttendanceSheet)
+	admin.Get("/attendance/:id", insideController.AttendanceSheet)
+	teacher.Get("/export/checklist/:id", exportController.ExportChecklist)
+	admin.Get("/export/checklist/:id", exportController.ExportChecklist)
+
+	student.Get("/certificate/:id", certificateController.MyCertificate)
+	teacher.Get("/certificates/:id", certificateController.EventCertificates)
+	admin.Get("/certificates/:id", certificateController.EventCertificates)
+
+	student.Put("/feedback/:id", feedbackController.SubmitFeedback)
+	student.Get("/feedback/:id", feedbackController.MyFeedback)
+	teacher.Get("/event/:id/feedback", feedbackController.EventFeedback)
+	admin.Get("/event/:id/feedback", feedbackController.EventFeedback)
+	admin.Get("/feedback", feedbackController.ListFeedback)
+	admin.Put("/feedback/:id/moderation", feedbackController.ModerateFeedback)
+
+	student.Post("/attachment/:kind/:id", attachmentController.UploadAttachment)
+	student.Delete("/attachment/:id", attachmentController.DeleteAttachment)
+	protected.Get("/attachments/:kind/:id/:userid", attachmentController.GetAttachments)
+	protected.Get("/attachment/:id", attachmentController.GetAttachment)
+	protected.Get("/attachment/:id/thumbnail", attachmentController.GetThumbnail)
 
 	student.Post("/outside",outsideController.CreateOutside)
+	student.Get("/outside",outsideController.MyOutside)
 	student.Get("/outside/:id",outsideController.GetOutsideByID)
+	student.Put("/outside/:id",outsideController.EditOutside)
+	student.Delete("/outside/:id",outsideController.DeleteOutside)
 	student.Get("/download/:id",outsideController.DownloadPDF)
+	student.Get("/download/year/:year",outsideController.DownloadYearPDF)
+	staff.Get("/outside/:id",outsideController.GetOutsideByID)
+	staff.Get("/download/:id",outsideController.DownloadPDF)
+	staff.Get("/student/:id/outside/:year/download",outsideController.DownloadYearPDF)
 
 	student.Get("myevents/:year",eventController.AllMyEventThisYear)
+	student.Get("/transcript", transcriptController.DownloadTranscript)
+	student.Get("/transcript/:year", transcriptController.DownloadTranscript)
+	staff.Get("/student/:id/transcript", transcriptController.DownloadTranscript)
+	staff.Get("/student/:id/transcript/:year", transcriptController.DownloadTranscript)
+	student.Get("/requirement", requirementController.Evaluate)
+	staff.Get("/student/:id/requirement", requirementController.Evaluate)
+
+	staff.Get("/faculties", staffController.MyFaculties)
+	staff.Get("/students", staffController.GetStudents)
+	staff.Put("/studentinfo/:id", staffController.EditStudent)
+	staff.Get("/student/:id/events/:year", staffController.GetStudentEvents)
+	staff.Get("/events", staffController.GetEvents)
+	staff.Get("/checklist/:id", staffController.GetChecklist)
+	staff.Put("/check/:id/:userid", staffController.ConfirmAndCheck)
+	staff.Put("/check/:id", insideController.StaffBulkConfirmAndCheck)
+	staff.Get("/export/checklist/:id", exportController.StaffExportChecklist)
+	staff.Get("/export/participations/:year", exportController.ExportParticipations)
+	staff.Get("/export/hours/:year", exportController.ExportStudentHours)
 
 
 }
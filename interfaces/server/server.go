@@ -4,6 +4,9 @@ import (
 	"RESTAPI/config"
 	"RESTAPI/infrastructure/database"
 	"RESTAPI/infrastructure/jwt"
+	"RESTAPI/infrastructure/mailer"
+	"RESTAPI/infrastructure/storage"
+	"RESTAPI/utility/fileSystem"
 	// "RESTAPI/infrastructure/redis"
 	"fmt"
 
@@ -25,13 +28,17 @@ type fiberServer struct {
 }
 
 // NewServer ฟังก์ชันสำหรับสร้าง instance ของเซิร์ฟเวอร์ Fiber
-func NewServer(cfg *config.Config, db database.Database ,jwtService *jwt.JWTService ) (Server, error) {
+func NewServer(cfg *config.Config, db database.Database ,jwtService *jwt.JWTService, store storage.Storage, validator *filesystem.PDFValidator, renderer *filesystem.PDFRenderer, mail mailer.Mailer) (Server, error) {
 	// ตรวจสอบค่าพอร์ต
 	if cfg.ServerPort == 0 {
 		return nil, fmt.Errorf("Server port not specified in config")
 	}
 
-	app := fiber.New()
+	// fiber อ่าน body ทั้งหมดก่อนถึง middleware จึงตั้งเพดานไว้ที่ขนาดของเส้นทางอัปโหลด
+	// เส้นทางอื่นถูกจำกัดที่ขนาดปกติด้วย middleware.BodyLimit ใน SetupRoutes
+	app := fiber.New(fiber.Config{
+		BodyLimit: uploadBodyLimit(cfg),
+	})
 
 	app.Use(cors.New(cors.Config{
         AllowOrigins: "http://localhost:3000/, http://127.0.0.1:8080", // อนุญาตเฉพาะ origin ที่ระบุ
@@ -40,7 +47,6 @@ func NewServer(cfg *config.Config, db database.Database ,jwtService *jwt.JWTServ
         AllowCredentials: true,                                                // รองรับ cookies
     }))
 	
-	app.Static("/uploads", "./uploads")
 	// กำหนด middleware สำหรับการกู้คืนจาก panic
 	app.Use(recover.New())
 
@@ -48,7 +54,7 @@ func NewServer(cfg *config.Config, db database.Database ,jwtService *jwt.JWTServ
 	app.Use(logger.New())
 
 	// กำหนดเส้นทางทั้งหมดและส่งผ่านฐานข้อมูล
-	SetupRoutes(app, db,jwtService)
+	SetupRoutes(app, cfg, db,jwtService, store, validator, renderer, mail)
 
 	return &fiberServer{
 		app:  app,
@@ -2,10 +2,17 @@ package main
 
 import (
 	"RESTAPI/config"
+	"RESTAPI/domain/repository"
 	"RESTAPI/infrastructure/database"
 	"RESTAPI/infrastructure/jwt"
+	"RESTAPI/infrastructure/mailer"
+	"RESTAPI/infrastructure/scanner"
+	"RESTAPI/infrastructure/storage"
 	"RESTAPI/interfaces/server"
+	"RESTAPI/usecase"
+	"RESTAPI/utility/fileSystem"
 	"log"
+	"os"
 	"time"
 )
 
@@ -25,6 +32,37 @@ func deleteOldNews(db database.Database) {
 	}
 }
 
+// purgeSupersededEvidence ลบไฟล์หลักฐานเวอร์ชันเก่าที่เกินระยะเวลาเก็บรักษา วันละครั้ง
+func purgeSupersededEvidence(maintenance usecase.MaintenanceUsecase, retention time.Duration) {
+	for {
+		purged, err := maintenance.PurgeSupersededEvidence(retention)
+		if err != nil {
+			log.Printf("Error purging superseded evidence: %v", err)
+		} else {
+			log.Printf("Purged %d superseded evidence files successfully.", purged)
+		}
+
+		time.Sleep(24 * time.Hour)
+	}
+}
+
+// reconcileStorage ตรวจไฟล์กำพร้าและไฟล์ที่หายไปตามรอบที่ตั้งค่าไว้
+// ค่าเริ่มต้นรายงานอย่างเดียว จะลบหรือล้างการอ้างอิงจริงเมื่อตั้ง STORAGE_GC_REPAIR=true
+// (storage ที่ mount ผิดหรือว่างเปล่าจะทำให้ทุกไฟล์ดูเหมือนหายไป) หรือสั่งเองด้วย ./app storage-gc
+func reconcileStorage(maintenance usecase.MaintenanceUsecase, interval time.Duration, repair bool) {
+	for {
+		report, err := maintenance.ReconcileStorage(!repair, storageGCGrace)
+		if err != nil {
+			log.Printf("Error reconciling storage: %v", err)
+		} else if repair {
+			log.Printf("Storage reconciled: removed %d orphaned files, repaired %d missing files.", report.Removed, report.Repaired)
+		} else {
+			log.Printf("Storage check (report only): %d orphaned files, %d missing files. Run storage-gc to repair.", len(report.Orphans), len(report.Missing))
+		}
+
+		time.Sleep(interval)
+	}
+}
 
 func main() {
 	// โหลดค่าคอนฟิกจากไฟล์ .env
@@ -33,14 +71,51 @@ func main() {
 	// ตั้งค่าและเชื่อมต่อฐานข้อมูล
 	db := database.SetupDatabase(cfg)
 
+	// สร้างที่เก็บไฟล์ตาม STORAGE_DRIVER
+	store, err := storage.NewStorage(cfg)
+	if err != nil {
+		log.Fatalf("failed to create storage: %v", err)
+	}
+	maintenance := usecase.NewMaintenanceUsecase(repository.NewEvidenceRepository(db.GetDb()), repository.NewFileReferenceRepository(db.GetDb()), store)
+
+	// คำสั่งสำหรับผู้ดูแลระบบ เช่น ./app storage-gc -dry-run
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, maintenance, os.Args[1:]))
+	}
+
 	// เริ่มรัน goroutine สำหรับการลบข้อมูลเก่า
 	go deleteOldNews(db)
 
 	// สร้าง instance ของ JWT service
 	jwtService := jwt.NewJWTService(cfg)
 
+	// ตัวตรวจไฟล์ PDF ที่อัปโหลด ถ้าตั้ง CLAMD_ADDR จะตรวจมัลแวร์ด้วย clamd
+	fileScanner := scanner.NewNoopScanner()
+	if cfg.Upload.ClamdAddr != "" {
+		fileScanner = scanner.NewClamdScanner(cfg.Upload.ClamdAddr)
+	}
+	validator := filesystem.NewPDFValidator(cfg.Upload.MaxSize, cfg.Upload.MaxPages, fileScanner)
+
+	// เริ่มรัน goroutine สำหรับลบไฟล์หลักฐานเวอร์ชันเก่าและไฟล์กำพร้า
+	go purgeSupersededEvidence(maintenance, cfg.Upload.EvidenceRetention)
+	if cfg.Storage.GCInterval > 0 {
+		go reconcileStorage(maintenance, cfg.Storage.GCInterval, cfg.Storage.GCRepair)
+	}
+
+	// โหลดฟอนต์ รูปภาพ และแม่แบบสำหรับสร้างเอกสาร PDF
+	renderer, err := filesystem.NewPDFRenderer(cfg.PDFTemplateDir)
+	if err != nil {
+		log.Fatalf("failed to create PDF renderer: %v", err)
+	}
+
+	// ส่งอีเมลผ่าน SMTP ถ้าตั้ง SMTP_HOST ไว้
+	mail := mailer.NewNoopMailer()
+	if cfg.Mail.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUser, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	}
+
 	// สร้าง instance ของ server
-	srv, err := server.NewServer(cfg, db, jwtService)
+	srv, err := server.NewServer(cfg, db, jwtService, store, validator, renderer, mail)
 	if err != nil {
 		log.Fatalf("failed to create server: %v", err)
 	}
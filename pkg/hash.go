@@ -1,10 +1,15 @@
 package pkg
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ไม่ใช้ตัวอักษรที่สับสนกันง่าย เช่น 0/O และ 1/l/I
+const passwordLetters = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz23456789"
+
 func HashPassword(password string) (string, error) {
     if len(password) == 0 {
         return "", fmt.Errorf("password cannot be empty")
@@ -21,3 +26,17 @@ func CheckPasswordHash(password, hash string) bool {
     err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
     return err == nil
 }
+
+// GeneratePassword สุ่มรหัสผ่านเริ่มต้นความยาว length ตัวอักษร
+func GeneratePassword(length int) (string, error) {
+    password := make([]byte, length)
+    max := big.NewInt(int64(len(passwordLetters)))
+    for i := range password {
+        n, err := rand.Int(rand.Reader, max)
+        if err != nil {
+            return "", fmt.Errorf("failed to generate password: %w", err)
+        }
+        password[i] = passwordLetters[n.Int64()]
+    }
+    return string(password), nil
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/utility/cache"
+	"fmt"
+	"time"
+)
+
+// AnalyticsUsecase สถิติรวมสำหรับผู้ดูแลระบบ ผลลัพธ์ถูกเก็บไว้ตามอายุ cacheTTL ปีการศึกษา 0 คือทุกปี
+type AnalyticsUsecase interface {
+	Participation(schoolYear uint, groupBy string) ([]entities.ParticipationStat, error)
+	FillRates(schoolYear uint) ([]entities.EventFillRate, error)
+	TopOrganizers(schoolYear uint, limit int) ([]entities.OrganizerStat, error)
+	Turnaround(schoolYear uint) (*entities.TurnaroundReport, error)
+	Requirement(schoolYear uint) (*entities.RequirementReport, error)
+	Categories(schoolYear uint) ([]entities.CategoryStat, error)
+}
+
+type analyticsUsecase struct {
+	analyticsRepo      repository.AnalyticsRepository
+	requirementUsecase RequirementUsecase
+	cache              *cache.Cache
+}
+
+// NewAnalyticsUsecase cacheTTL เป็น 0 คือไม่เก็บผลลัพธ์
+func NewAnalyticsUsecase(analyticsRepo repository.AnalyticsRepository, requirementUsecase RequirementUsecase, cacheTTL time.Duration) AnalyticsUsecase {
+	return &analyticsUsecase{
+		analyticsRepo:      analyticsRepo,
+		requirementUsecase: requirementUsecase,
+		cache:              cache.New(cacheTTL),
+	}
+}
+
+// Participation groupBy เป็น faculty, branch, year (ชั้นปี) หรือ school_year ค่าว่างคือ faculty
+func (u *analyticsUsecase) Participation(schoolYear uint, groupBy string) ([]entities.ParticipationStat, error) {
+	switch groupBy {
+	case "":
+		groupBy = repository.GroupByFaculty
+	case repository.GroupByFaculty, repository.GroupByBranch, repository.GroupByYear, repository.GroupBySchoolYear:
+	default:
+		return nil, fmt.Errorf("%w: unknown group_by %q", ErrInvalidRequest, groupBy)
+	}
+	value, err := u.cache.Get(fmt.Sprintf("participation:%d:%s", schoolYear, groupBy), func() (interface{}, error) {
+		return u.analyticsRepo.ParticipationStats(schoolYear, groupBy)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participation stats: %w", err)
+	}
+	return value.([]entities.ParticipationStat), nil
+}
+
+func (u *analyticsUsecase) FillRates(schoolYear uint) ([]entities.EventFillRate, error) {
+	value, err := u.cache.Get(fmt.Sprintf("fillrates:%d", schoolYear), func() (interface{}, error) {
+		rates, err := u.analyticsRepo.EventFillRates(schoolYear)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rates {
+			if rates[i].Capacity > 0 {
+				rates[i].FillRate = float64(rates[i].Joined) / float64(rates[i].Capacity)
+			}
+		}
+		return rates, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fill rates: %w", err)
+	}
+	return value.([]entities.EventFillRate), nil
+}
+
+// TopOrganizers limit อยู่ระหว่าง 1-100
+func (u *analyticsUsecase) TopOrganizers(schoolYear uint, limit int) ([]entities.OrganizerStat, error) {
+	if limit < 1 || limit > 100 {
+		return nil, fmt.Errorf("%w: limit must be between 1 and 100", ErrInvalidRequest)
+	}
+	value, err := u.cache.Get(fmt.Sprintf("organizers:%d:%d", schoolYear, limit), func() (interface{}, error) {
+		return u.analyticsRepo.TopOrganizers(schoolYear, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organizers: %w", err)
+	}
+	return value.([]entities.OrganizerStat), nil
+}
+
+// Turnaround ค่ารวมทุกคณะถ่วงน้ำหนักตามจำนวนที่รับรองของแต่ละคณะ
+func (u *analyticsUsecase) Turnaround(schoolYear uint) (*entities.TurnaroundReport, error) {
+	value, err := u.cache.Get(fmt.Sprintf("turnaround:%d", schoolYear), func() (interface{}, error) {
+		stats, err := u.analyticsRepo.TurnaroundByFaculty(schoolYear)
+		if err != nil {
+			return nil, err
+		}
+		report := &entities.TurnaroundReport{
+			Overall:   entities.TurnaroundStat{GroupName: "ทั้งหมด"},
+			Faculties: stats,
+		}
+		var total float64
+		for i, stat := range stats {
+			if i == 0 || stat.MinHours < report.Overall.MinHours {
+				report.Overall.MinHours = stat.MinHours
+			}
+			if stat.MaxHours > report.Overall.MaxHours {
+				report.Overall.MaxHours = stat.MaxHours
+			}
+			report.Overall.Certified += stat.Certified
+			total += stat.AverageHours * float64(stat.Certified)
+		}
+		if report.Overall.Certified > 0 {
+			report.Overall.AverageHours = total / float64(report.Overall.Certified)
+		}
+		return report, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get turnaround: %w", err)
+	}
+	return value.(*entities.TurnaroundReport), nil
+}
+
+// Requirement ต้องระบุปีการศึกษา นักศึกษาแต่ละคนประเมินตามเกณฑ์ที่ตรงกับคณะ สาขา และชั้นปีของตัวเอง
+// นักศึกษาที่ไม่มีเกณฑ์ใดใช้กับตนถือว่าครบ
+func (u *analyticsUsecase) Requirement(schoolYear uint) (*entities.RequirementReport, error) {
+	if schoolYear == 0 {
+		return nil, fmt.Errorf("%w: school_year is required", ErrInvalidRequest)
+	}
+	value, err := u.cache.Get(fmt.Sprintf("requirement:%d", schoolYear), func() (interface{}, error) {
+		evaluate, err := u.requirementUsecase.Evaluator(schoolYear)
+		if err != nil {
+			return nil, err
+		}
+		students, err := u.analyticsRepo.StudentScopes()
+		if err != nil {
+			return nil, err
+		}
+		stats := []entities.RequirementStat{}
+		for _, s := range students {
+			if len(stats) == 0 || stats[len(stats)-1].GroupID != s.FacultyID {
+				stats = append(stats, entities.RequirementStat{GroupID: s.FacultyID, GroupName: s.FacultyName})
+			}
+			stat := &stats[len(stats)-1]
+			stat.Students++
+			if evaluate(s.UserID, s.FacultyID, s.BranchID, s.ClassYear).Met {
+				stat.OnTrack++
+			}
+		}
+		report := &entities.RequirementReport{
+			Overall:   entities.RequirementStat{GroupName: "ทั้งหมด"},
+			Faculties: stats,
+		}
+		for i := range stats {
+			if stats[i].Students > 0 {
+				stats[i].Share = float64(stats[i].OnTrack) / float64(stats[i].Students)
+			}
+			report.Overall.Students += stats[i].Students
+			report.Overall.OnTrack += stats[i].OnTrack
+		}
+		if report.Overall.Students > 0 {
+			report.Overall.Share = float64(report.Overall.OnTrack) / float64(report.Overall.Students)
+		}
+		return report, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirement stats: %w", err)
+	}
+	return value.(*entities.RequirementReport), nil
+}
+
+func (u *analyticsUsecase) Categories(schoolYear uint) ([]entities.CategoryStat, error) {
+	value, err := u.cache.Get(fmt.Sprintf("categories:%d", schoolYear), func() (interface{}, error) {
+		return u.analyticsRepo.CategoryStats(schoolYear)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category stats: %w", err)
+	}
+	return value.([]entities.CategoryStat), nil
+}
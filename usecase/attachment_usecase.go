@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/infrastructure/storage"
+	"RESTAPI/utility/fileSystem"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+)
+
+// ประเภทไฟล์แนบ
+const (
+	AttachmentTypePDF   = "pdf"
+	AttachmentTypeImage = "image"
+)
+
+// AttachmentUsecase ไฟล์หลักฐานแนบหลายไฟล์ต่อการเข้าร่วม ทั้งกิจกรรมภายในและภายนอก
+type AttachmentUsecase interface {
+	UploadAttachment(kind string, eventID uint, userID uint, file *multipart.FileHeader) (*entities.Attachment, error)
+	GetAttachments(kind string, eventID uint, ownerID uint, requesterID uint, role string) ([]entities.Attachment, error)
+	OpenAttachment(id uint, requesterID uint, role string, thumbnail bool) (io.ReadCloser, *entities.Attachment, error)
+	DeleteAttachment(id uint, userID uint) error
+}
+
+type attachmentUsecase struct {
+	attachmentRepo repository.AttachmentRepository
+	insideRepo     repository.EventInsideRepository
+	insideUsecase  EventInsideUsecase
+	outsideUsecase OutsideUsecase
+	store          storage.Storage
+	validator      *filesystem.PDFValidator
+	maxAttachments int
+}
+
+func NewAttachmentUsecase(attachmentRepo repository.AttachmentRepository, insideRepo repository.EventInsideRepository, insideUsecase EventInsideUsecase, outsideUsecase OutsideUsecase, store storage.Storage, validator *filesystem.PDFValidator, maxAttachments int) AttachmentUsecase {
+	return &attachmentUsecase{
+		attachmentRepo: attachmentRepo,
+		insideRepo:     insideRepo,
+		insideUsecase:  insideUsecase,
+		outsideUsecase: outsideUsecase,
+		store:          store,
+		validator:      validator,
+		maxAttachments: maxAttachments,
+	}
+}
+
+// checkOwner ผู้อัปโหลดต้องเป็นผู้เข้าร่วมกิจกรรมภายใน หรือเจ้าของกิจกรรมภายนอก
+func (u *attachmentUsecase) checkOwner(kind string, eventID uint, userID uint) error {
+	switch kind {
+	case entities.AttachmentInside:
+		joined, err := u.insideRepo.IsUserJoinedEvent(eventID, userID)
+		if err != nil {
+			return err
+		}
+		if !joined {
+			return fmt.Errorf("user is not a member of this event")
+		}
+	case entities.AttachmentOutside:
+		outside, err := u.outsideUsecase.CanAccess(eventID, userID, "student")
+		if err != nil {
+			return err
+		}
+		if outside.User != userID {
+			return fmt.Errorf("%w: you cannot attach files to this record", ErrPermissionDenied)
+		}
+	default:
+		return fmt.Errorf("invalid attachment kind %q", kind)
+	}
+	return nil
+}
+
+// canView ใช้สิทธิ์เดียวกับการดูไฟล์หลักฐานเดิมของการเข้าร่วมนั้น
+func (u *attachmentUsecase) canView(kind string, eventID uint, ownerID uint, requesterID uint, role string) error {
+	switch kind {
+	case entities.AttachmentInside:
+		return u.insideUsecase.CanViewFile(eventID, ownerID, requesterID, role)
+	case entities.AttachmentOutside:
+		outside, err := u.outsideUsecase.CanAccess(eventID, requesterID, role)
+		if err != nil {
+			return err
+		}
+		if outside.User != ownerID {
+			return fmt.Errorf("event with ID %d not found", eventID)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid attachment kind %q", kind)
+}
+
+func (u *attachmentUsecase) UploadAttachment(kind string, eventID uint, userID uint, file *multipart.FileHeader) (*entities.Attachment, error) {
+	if err := u.checkOwner(kind, eventID, userID); err != nil {
+		return nil, err
+	}
+	if file.Size > u.validator.MaxSize {
+		return nil, &filesystem.ValidationError{Code: filesystem.CodeFileTooLarge, Message: fmt.Sprintf("file exceeds the maximum size of %d bytes", u.validator.MaxSize)}
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(src, u.validator.MaxSize+1))
+	src.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	attachment := &entities.Attachment{
+		Kind:       kind,
+		EventID:    eventID,
+		UserID:     userID,
+		FileName:   filepath.Base(file.Filename),
+		UploadedBy: userID,
+	}
+	if len(attachment.FileName) > 255 {
+		attachment.FileName = attachment.FileName[:255]
+	}
+
+	// ตรวจชนิดไฟล์จากเนื้อหา ไม่ใช้ Content-Type หรือนามสกุลที่ client ส่งมา
+	var thumbnail []byte
+	ext := "pdf"
+	switch http.DetectContentType(data) {
+	case "application/pdf":
+		if err := u.validator.Validate(data); err != nil {
+			return nil, err
+		}
+		attachment.Type = AttachmentTypePDF
+		attachment.ContentType = "application/pdf"
+	case "image/jpeg", "image/png":
+		if err := u.validator.Scan(data); err != nil {
+			return nil, err
+		}
+		img, err := filesystem.ProcessImage(data)
+		if err != nil {
+			return nil, err
+		}
+		data, thumbnail, ext = img.Data, img.Thumbnail, img.Ext
+		attachment.Type = AttachmentTypeImage
+		attachment.ContentType = img.ContentType
+	default:
+		return nil, &filesystem.ValidationError{Code: filesystem.CodeUnsupportedType, Message: "only PDF, JPEG and PNG files are allowed"}
+	}
+
+	sum := sha256.Sum256(data)
+	attachment.Checksum = hex.EncodeToString(sum[:])
+	attachment.Size = int64(len(data))
+
+	key, err := filesystem.SaveBytes(u.store, data, userID, ext, attachment.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+	attachment.FileKey = key
+	if thumbnail != nil {
+		thumbKey, err := filesystem.SaveBytes(u.store, thumbnail, userID, "thumb.jpg", "image/jpeg")
+		if err != nil {
+			u.store.Delete(key)
+			return nil, fmt.Errorf("failed to save thumbnail: %w", err)
+		}
+		attachment.ThumbnailKey = thumbKey
+	}
+
+	if err := u.attachmentRepo.CreateAttachment(attachment, u.maxAttachments); err != nil {
+		u.store.Delete(attachment.FileKey)
+		if attachment.ThumbnailKey != "" {
+			u.store.Delete(attachment.ThumbnailKey)
+		}
+		if errors.Is(err, repository.ErrAttachmentLimit) {
+			return nil, &filesystem.ValidationError{Code: filesystem.CodeAttachmentLimit, Message: fmt.Sprintf("a participation can have at most %d attachments", u.maxAttachments)}
+		}
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (u *attachmentUsecase) GetAttachments(kind string, eventID uint, ownerID uint, requesterID uint, role string) ([]entities.Attachment, error) {
+	if err := u.canView(kind, eventID, ownerID, requesterID, role); err != nil {
+		return nil, err
+	}
+	attachments, err := u.attachmentRepo.GetAttachments(kind, eventID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []entities.Attachment{}
+	}
+	return attachments, nil
+}
+
+// OpenAttachment เปิดไฟล์แนบ (หรือ thumbnail) ผู้เรียกต้องปิด reader เอง
+func (u *attachmentUsecase) OpenAttachment(id uint, requesterID uint, role string, thumbnail bool) (io.ReadCloser, *entities.Attachment, error) {
+	attachment, err := u.attachmentRepo.GetAttachment(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.canView(attachment.Kind, attachment.EventID, attachment.UserID, requesterID, role); err != nil {
+		return nil, nil, err
+	}
+	key := attachment.FileKey
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, fmt.Errorf("attachment has no thumbnail")
+		}
+		key = attachment.ThumbnailKey
+	}
+	file, err := u.store.Open(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, attachment, nil
+}
+
+// DeleteAttachment ลบได้เฉพาะเจ้าของไฟล์
+func (u *attachmentUsecase) DeleteAttachment(id uint, userID uint) error {
+	attachment, err := u.attachmentRepo.GetAttachment(id)
+	if err != nil {
+		return err
+	}
+	if attachment.UserID != userID {
+		return fmt.Errorf("%w: you cannot delete this attachment", ErrPermissionDenied)
+	}
+	if err := u.attachmentRepo.DeleteAttachment(id); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if err := u.store.Delete(attachment.FileKey); err != nil {
+		return fmt.Errorf("attachment deleted but failed to remove file: %w", err)
+	}
+	if attachment.ThumbnailKey != "" {
+		if err := u.store.Delete(attachment.ThumbnailKey); err != nil {
+			return fmt.Errorf("attachment deleted but failed to remove thumbnail: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/utility"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ขีดจำกัดของแท็กต่อกิจกรรม
+const (
+	maxEventTags  = 10
+	maxTagLength  = 50
+	uncategorized = "ไม่ระบุหมวดหมู่"
+)
+
+type CategoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SDGs        []uint `json:"sdgs"`
+}
+
+// CategoryUsecase หมวดหมู่ของกิจกรรม แก้ไขได้เฉพาะผู้ดูแลระบบ
+type CategoryUsecase interface {
+	CreateCategory(req *CategoryRequest) (*entities.CategoryResponse, error)
+	GetAllCategories() ([]entities.CategoryResponse, error)
+	GetCategory(id uint) (*entities.CategoryResponse, error)
+	UpdateCategory(id uint, req *CategoryRequest) (*entities.CategoryResponse, error)
+	DeleteCategory(id uint) error
+}
+
+type categoryUsecase struct {
+	categoryRepo repository.CategoryRepository
+}
+
+func NewCategoryUsecase(categoryRepo repository.CategoryRepository) CategoryUsecase {
+	return &categoryUsecase{categoryRepo: categoryRepo}
+}
+
+// encodeSDGs ตรวจหมายเลข SDG (1-17 ไม่ซ้ำ) แล้วแปลงเป็น JSON เรียงตามหมายเลข
+func encodeSDGs(sdgs []uint) (string, error) {
+	seen := map[uint]bool{}
+	var list []uint
+	for _, sdg := range sdgs {
+		if sdg < 1 || sdg > entities.MaxSDG {
+			return "", fmt.Errorf("%w: sdg must be between 1 and %d", ErrInvalidRequest, entities.MaxSDG)
+		}
+		if !seen[sdg] {
+			seen[sdg] = true
+			list = append(list, sdg)
+		}
+	}
+	if list == nil {
+		list = []uint{}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	data, err := json.Marshal(list)
+	return string(data), err
+}
+
+// encodeTags ตัดช่องว่าง แปลงเป็นตัวพิมพ์เล็ก และตัดแท็กที่ซ้ำ
+func encodeTags(tags []string) (string, error) {
+	seen := map[string]bool{}
+	list := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return "", fmt.Errorf("%w: tag must be at most %d characters", ErrInvalidRequest, maxTagLength)
+		}
+		seen[tag] = true
+		list = append(list, tag)
+	}
+	if len(list) > maxEventTags {
+		return "", fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidRequest, maxEventTags)
+	}
+	data, err := json.Marshal(list)
+	return string(data), err
+}
+
+// findCategory หมวดหมู่ตาม id ถ้า id เป็น nil คือไม่ระบุหมวดหมู่
+func findCategory(categoryRepo repository.CategoryRepository, id *uint) (*entities.Category, error) {
+	if id == nil {
+		return nil, nil
+	}
+	category, err := categoryRepo.GetCategory(*id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, fmt.Errorf("%w: category %d does not exist", ErrInvalidRequest, *id)
+	}
+	return category, nil
+}
+
+func mapCategoryResponse(category *entities.Category) (*entities.CategoryResponse, error) {
+	if category == nil {
+		return nil, nil
+	}
+	sdgs, err := utility.DecodeIDs(category.SDGs)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.CategoryResponse{
+		CategoryID:  category.CategoryID,
+		Name:        category.Name,
+		Description: category.Description,
+		SDGs:        sdgs,
+	}, nil
+}
+
+// categoryName ชื่อหมวดหมู่สำหรับแสดงผล
+func categoryName(category *entities.Category) string {
+	if category == nil {
+		return ""
+	}
+	return category.Name
+}
+
+func (u *categoryUsecase) apply(category *entities.Category, req *CategoryRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRequest)
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return fmt.Errorf("%w: name must be at most 100 characters", ErrInvalidRequest)
+	}
+	existing, err := u.categoryRepo.GetCategoryByName(name)
+	if err != nil {
+		return fmt.Errorf("failed to check category name: %w", err)
+	}
+	if existing != nil && existing.CategoryID != category.CategoryID {
+		return fmt.Errorf("%w: category %q already exists", ErrInvalidRequest, name)
+	}
+	sdgs, err := encodeSDGs(req.SDGs)
+	if err != nil {
+		return err
+	}
+	category.Name = name
+	category.Description = strings.TrimSpace(req.Description)
+	category.SDGs = sdgs
+	return nil
+}
+
+func (u *categoryUsecase) CreateCategory(req *CategoryRequest) (*entities.CategoryResponse, error) {
+	category := &entities.Category{}
+	if err := u.apply(category, req); err != nil {
+		return nil, err
+	}
+	if err := u.categoryRepo.CreateCategory(category); err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	return mapCategoryResponse(category)
+}
+
+func (u *categoryUsecase) GetAllCategories() ([]entities.CategoryResponse, error) {
+	categories, err := u.categoryRepo.GetAllCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	res := []entities.CategoryResponse{}
+	for i := range categories {
+		category, err := mapCategoryResponse(&categories[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *category)
+	}
+	return res, nil
+}
+
+func (u *categoryUsecase) GetCategory(id uint) (*entities.CategoryResponse, error) {
+	category, err := u.categoryRepo.GetCategory(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, fmt.Errorf("%w: category %d", ErrNotFound, id)
+	}
+	return mapCategoryResponse(category)
+}
+
+func (u *categoryUsecase) UpdateCategory(id uint, req *CategoryRequest) (*entities.CategoryResponse, error) {
+	category, err := u.categoryRepo.GetCategory(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, fmt.Errorf("%w: category %d", ErrNotFound, id)
+	}
+	if err := u.apply(category, req); err != nil {
+		return nil, err
+	}
+	if err := u.categoryRepo.UpdateCategory(category); err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+	return mapCategoryResponse(category)
+}
+
+// DeleteCategory ลบได้เฉพาะหมวดหมู่ที่ยังไม่มีกิจกรรมหรือเกณฑ์ชั่วโมงใช้อยู่
+func (u *categoryUsecase) DeleteCategory(id uint) error {
+	count, err := u.categoryRepo.CountUsage(id)
+	if err != nil {
+		return fmt.Errorf("failed to check category usage: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: category is used by %d events or requirement rules", ErrInvalidRequest, count)
+	}
+	return u.categoryRepo.DeleteCategory(id)
+}
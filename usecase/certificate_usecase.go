@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/utility"
+	filesystem "RESTAPI/utility/fileSystem"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// CertificateUsecase เกียรติบัตรของผู้เข้าร่วมกิจกรรมภายในที่ได้รับการรับรองแล้ว
+type CertificateUsecase interface {
+	MyCertificate(eventID uint, userID uint) ([]byte, string, error)
+	EventCertificates(eventID uint, userID uint, role string) ([]byte, string, error)
+}
+
+type certificateUsecase struct {
+	insideRepo      repository.EventInsideRepository
+	organizerRepo   repository.OrganizerRepository
+	userRepo        repository.UserRepository
+	eventUsecase    EventUsecase
+	renderer        *filesystem.PDFRenderer
+	documentUsecase DocumentUsecase
+}
+
+func NewCertificateUsecase(insideRepo repository.EventInsideRepository, organizerRepo repository.OrganizerRepository, userRepo repository.UserRepository, eventUsecase EventUsecase, renderer *filesystem.PDFRenderer, documentUsecase DocumentUsecase) CertificateUsecase {
+	return &certificateUsecase{
+		insideRepo:      insideRepo,
+		organizerRepo:   organizerRepo,
+		userRepo:        userRepo,
+		eventUsecase:    eventUsecase,
+		renderer:        renderer,
+		documentUsecase: documentUsecase,
+	}
+}
+
+// certifier ชื่อและตำแหน่งของผู้รับรอง ใช้ผู้ที่รับรองจริง (CertifiedBy)
+// การรับรองก่อนมีการบันทึกผู้รับรองใช้ผู้สร้างกิจกรรม
+func (u *certificateUsecase) certifier(inside *entities.EventInside) (string, string, error) {
+	if inside.CertifiedBy == nil || *inside.CertifiedBy == inside.Event.Creator {
+		return teacherName(inside.Event.Teacher), "อาจารย์ผู้รับผิดชอบกิจกรรม", nil
+	}
+	teacher, err := u.userRepo.GetTeacherByUserID(*inside.CertifiedBy)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get certifier %d: %w", *inside.CertifiedBy, err)
+	}
+	organizer, err := u.organizerRepo.GetOrganizer(inside.EventId, teacher.UserID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to check organizer: %w", err)
+	}
+	if organizer != nil {
+		return teacherName(*teacher), "อาจารย์ผู้จัดกิจกรรมร่วม", nil
+	}
+	user, err := u.userRepo.GetUser(teacher.UserID)
+	if err == nil && user.Role == "admin" {
+		return teacherName(*teacher), "ผู้ดูแลระบบกิจกรรม", nil
+	}
+	return teacherName(*teacher), "เจ้าหน้าที่" + inside.Student.Branch.Faculty.FacultyName, nil
+}
+
+func teacherName(teacher entities.Teacher) string {
+	if teacher.FirstName == "" {
+		return ""
+	}
+	return teacher.TitleName + teacher.FirstName + " " + teacher.LastName
+}
+
+// render สร้างเกียรติบัตรพร้อม QR code และลงทะเบียนเอกสาร issuerID คือผู้ที่ขอออกเอกสาร
+func (u *certificateUsecase) render(inside *entities.EventInside, issuerID uint) ([]byte, error) {
+	name, position, err := u.certifier(inside)
+	if err != nil {
+		return nil, err
+	}
+	issuedAt := time.Now()
+	if inside.CertifiedAt != nil {
+		issuedAt = *inside.CertifiedAt
+	}
+	data := filesystem.CertificateData{
+		StudentName:       inside.Student.TitleName + inside.Student.FirstName + " " + inside.Student.LastName,
+		StudentCode:       inside.Student.Code,
+		FacultyName:       inside.Student.Branch.Faculty.FacultyName,
+		EventName:         inside.Event.EventName,
+		Location:          inside.Event.Location,
+		StartDate:         inside.Event.StartDate,
+		WorkingHour:       inside.Event.WorkingHour,
+		CertifierName:     name,
+		CertifierPosition: position,
+		IssuedAt:          issuedAt,
+	}
+	document := &entities.IssuedDocument{
+		Type:       entities.DocumentCertificate,
+		OwnerID:    inside.User,
+		EventID:    inside.EventId,
+		SchoolYear: inside.Event.SchoolYear,
+		Title:      "เกียรติบัตรการเข้าร่วมกิจกรรม " + inside.Event.EventName,
+		Details:    fmt.Sprintf("เข้าร่วมกิจกรรม %s เมื่อวันที่ %s รวม %d ชั่วโมง รับรองโดย %s (%s)", inside.Event.EventName, utility.FormatToThaiDate(inside.Event.StartDate), inside.Event.WorkingHour, name, position),
+		IssuedBy:   issuerID,
+	}
+	return u.documentUsecase.Issue(document, filesystem.TemplateCertificate, func(verification *filesystem.Verification) ([]byte, error) {
+		data.Verification = verification
+		return u.renderer.Certificate(data)
+	})
+}
+
+// MyCertificate เกียรติบัตรของนักศึกษาเอง ออกได้เมื่อการเข้าร่วมได้รับการรับรองแล้วเท่านั้น
+func (u *certificateUsecase) MyCertificate(eventID uint, userID uint) ([]byte, string, error) {
+	inside, err := u.insideRepo.GetParticipation(eventID, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get participation: %w", err)
+	}
+	if inside == nil {
+		return nil, "", fmt.Errorf("%w: you did not join event %d", ErrNotFound, eventID)
+	}
+	if !inside.Status {
+		return nil, "", fmt.Errorf("%w: participation has not been approved yet", ErrPermissionDenied)
+	}
+	data, err := u.render(inside, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return data, fmt.Sprintf("certificate_%d_%s.pdf", eventID, inside.Student.Code), nil
+}
+
+// EventCertificates เกียรติบัตรของผู้เข้าร่วมที่ได้รับการรับรองทั้งหมดในกิจกรรม รวมเป็นไฟล์ ZIP
+// ใช้ได้เฉพาะ admin ผู้สร้าง และผู้จัดร่วมที่มีสิทธิ์รับรอง
+func (u *certificateUsecase) EventCertificates(eventID uint, userID uint, role string) ([]byte, string, error) {
+	if role != "admin" {
+		allowed, err := u.eventUsecase.HasEventPermission(eventID, userID, PermissionCertify)
+		if err != nil {
+			return nil, "", err
+		}
+		if !allowed {
+			return nil, "", fmt.Errorf("%w: you cannot issue certificates for this event", ErrPermissionDenied)
+		}
+	}
+	participants, err := u.insideRepo.GetApprovedParticipants(eventID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get participants: %w", err)
+	}
+	if len(participants) == 0 {
+		return nil, "", fmt.Errorf("%w: event has no approved participants", ErrNotFound)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i := range participants {
+		data, err := u.render(&participants[i], userID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create certificate for user %d: %w", participants[i].User, err)
+		}
+		w, err := archive.Create(fmt.Sprintf("certificate_%d_%s.pdf", eventID, participants[i].Student.Code))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to create zip: %w", err)
+	}
+	return buf.Bytes(), fmt.Sprintf("certificates_event_%d.zip", eventID), nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"fmt"
+	"time"
+)
+
+// overdueListLimit จำนวนรายการที่รอตรวจเกินกำหนดที่ส่งกลับ (จำนวนใน Counts นับทั้งหมด)
+const overdueListLimit = 100
+
+// DashboardUsecase สรุปงานตรวจของทุกกิจกรรมที่อาจารย์สร้างหรือมีสิทธิ์รับรอง
+// รวมกิจกรรมของคณะที่อาจารย์เป็นเจ้าหน้าที่
+type DashboardUsecase interface {
+	TeacherDashboard(userID uint, markSeen bool) (*entities.TeacherDashboard, error)
+}
+
+type dashboardUsecase struct {
+	dashboardRepo repository.DashboardRepository
+	staffUsecase  StaffUsecase
+	overdueDays   uint
+}
+
+// NewDashboardUsecase overdueDays คือจำนวนวันหลังส่งหลักฐานที่ถือว่ารอตรวจเกินกำหนด
+func NewDashboardUsecase(dashboardRepo repository.DashboardRepository, staffUsecase StaffUsecase, overdueDays uint) DashboardUsecase {
+	return &dashboardUsecase{
+		dashboardRepo: dashboardRepo,
+		staffUsecase:  staffUsecase,
+		overdueDays:   overdueDays,
+	}
+}
+
+// staffEvents กิจกรรมที่เป็นของคณะตาม OwnsEvent ผู้ที่ไม่ได้เป็นเจ้าหน้าที่คณะใดไม่มีกิจกรรมเพิ่ม
+func (u *dashboardUsecase) staffEvents(userID uint) ([]uint, error) {
+	branchIDs, err := u.staffUsecase.BranchIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	eventIDs := []uint{}
+	if len(branchIDs) == 0 {
+		return eventIDs, nil
+	}
+	events, err := u.staffUsecase.GetEvents(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.EventID)
+	}
+	return eventIDs, nil
+}
+
+// TeacherDashboard หลักฐานใหม่นับจากการเข้าชมครั้งล่าสุด ถ้า markSeen จะบันทึกการเข้าชมครั้งนี้ไว้
+func (u *dashboardUsecase) TeacherDashboard(userID uint, markSeen bool) (*entities.TeacherDashboard, error) {
+	now := time.Now()
+	dashboard := &entities.TeacherDashboard{OverdueDays: u.overdueDays}
+	staffEvents, err := u.staffEvents(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faculty events: %w", err)
+	}
+
+	visit, err := u.dashboardRepo.GetLastVisit(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last visit: %w", err)
+	}
+	var since time.Time
+	if visit != nil {
+		since = visit.VisitedAt
+		dashboard.LastVisit = &visit.VisitedAt
+	}
+
+	if dashboard.AwaitingReview, err = u.dashboardRepo.PendingByEvent(userID, staffEvents); err != nil {
+		return nil, fmt.Errorf("failed to get pending reviews: %w", err)
+	}
+	for _, event := range dashboard.AwaitingReview {
+		dashboard.Counts.AwaitingReview += event.Pending
+	}
+
+	if dashboard.NewEvidence, err = u.dashboardRepo.NewEvidenceByEvent(userID, staffEvents, since); err != nil {
+		return nil, fmt.Errorf("failed to get new evidence: %w", err)
+	}
+	for _, event := range dashboard.NewEvidence {
+		dashboard.Counts.NewEvidence += event.Uploads
+	}
+
+	if dashboard.Upcoming, err = u.dashboardRepo.UpcomingEvents(userID, staffEvents, now); err != nil {
+		return nil, fmt.Errorf("failed to get upcoming events: %w", err)
+	}
+	for i := range dashboard.Upcoming {
+		if dashboard.Upcoming[i].Capacity > 0 {
+			dashboard.Upcoming[i].FillRate = float64(dashboard.Upcoming[i].Joined) / float64(dashboard.Upcoming[i].Capacity)
+		}
+	}
+	dashboard.Counts.Upcoming = uint(len(dashboard.Upcoming))
+
+	before := now.AddDate(0, 0, -int(u.overdueDays))
+	if dashboard.Counts.Overdue, err = u.dashboardRepo.CountOverdue(userID, staffEvents, before); err != nil {
+		return nil, fmt.Errorf("failed to count overdue reviews: %w", err)
+	}
+	if dashboard.Overdue, err = u.dashboardRepo.OverdueReviews(userID, staffEvents, before, overdueListLimit); err != nil {
+		return nil, fmt.Errorf("failed to get overdue reviews: %w", err)
+	}
+	for i := range dashboard.Overdue {
+		dashboard.Overdue[i].WaitingDays = uint(now.Sub(dashboard.Overdue[i].UploadedAt).Hours() / 24)
+	}
+
+	if markSeen {
+		if err := u.dashboardRepo.SaveVisit(&entities.DashboardVisit{UserID: userID, VisitedAt: now}); err != nil {
+			return nil, fmt.Errorf("failed to save visit: %w", err)
+		}
+	}
+	return dashboard, nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/infrastructure/jwt"
+	filesystem "RESTAPI/utility/fileSystem"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DocumentUsecase ลงทะเบียนเอกสารที่ระบบออก และตรวจสอบเอกสารจากรหัสใน QR code
+type DocumentUsecase interface {
+	Issue(document *entities.IssuedDocument, templateName string, render func(verification *filesystem.Verification) ([]byte, error)) ([]byte, error)
+	Verify(code string, file []byte) (*entities.DocumentVerification, error)
+}
+
+type documentUsecase struct {
+	documentRepo repository.DocumentRepository
+	jwtService   *jwt.JWTService
+	renderer     *filesystem.PDFRenderer
+	baseURL      string
+}
+
+// NewDocumentUsecase baseURL คือที่อยู่สาธารณะของระบบ ใช้สร้างลิงก์ตรวจสอบใน QR code
+func NewDocumentUsecase(documentRepo repository.DocumentRepository, jwtService *jwt.JWTService, renderer *filesystem.PDFRenderer, baseURL string) DocumentUsecase {
+	return &documentUsecase{
+		documentRepo: documentRepo,
+		jwtService:   jwtService,
+		renderer:     renderer,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Issue สร้างรหัสเอกสาร ให้ render พิมพ์ QR code แล้วบันทึก hash ของไฟล์ที่ได้ลงทะเบียน
+// ถ้าแหล่งที่มาเดียวกันเคยออกเอกสารแล้ว ใช้รหัสเดิมและเพิ่ม hash ของไฟล์ใหม่ในประวัติ สำเนาที่ออกไปก่อนหน้าจึงยังตรวจสอบผ่าน
+func (u *documentUsecase) Issue(document *entities.IssuedDocument, templateName string, render func(verification *filesystem.Verification) ([]byte, error)) ([]byte, error) {
+	existing, err := u.documentRepo.GetDocumentBySource(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	var code string
+	if existing != nil {
+		document.DocumentID = existing.DocumentID
+		code = existing.Code
+	} else if code, err = u.jwtService.GenerateDocumentCode(); err != nil {
+		return nil, fmt.Errorf("failed to generate document code: %w", err)
+	}
+	tpl, err := u.renderer.Template(templateName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := render(&filesystem.Verification{
+		Code: code,
+		URL:  u.baseURL + "/verify/" + code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	document.Code = code
+	document.TemplateVersion = tpl.Version
+	document.Checksum = hex.EncodeToString(sum[:])
+	document.IssuedAt = time.Now()
+	issue := &entities.DocumentIssue{
+		Checksum:        document.Checksum,
+		TemplateVersion: document.TemplateVersion,
+		Details:         document.Details,
+		IssuedBy:        document.IssuedBy,
+		IssuedAt:        document.IssuedAt,
+	}
+	if err := u.documentRepo.IssueDocument(document, issue); err != nil {
+		return nil, fmt.Errorf("failed to register document: %w", err)
+	}
+	return data, nil
+}
+
+// Verify รหัสที่ลายเซ็นไม่ถูกต้องหรือไม่มีในทะเบียนจะได้ Valid เป็น false
+// ถ้าส่ง file มาด้วยจะเทียบ hash กับไฟล์ทุกครั้งที่ออกภายใต้รหัสนี้ ว่าไฟล์ไม่ถูกแก้ไขหลังออกเอกสาร
+// ไฟล์ที่ตรงกับการออกครั้งก่อนจะแสดงรายละเอียดของครั้งนั้น
+func (u *documentUsecase) Verify(code string, file []byte) (*entities.DocumentVerification, error) {
+	result := &entities.DocumentVerification{Code: code}
+	if err := u.jwtService.ValidateDocumentCode(code); err != nil {
+		return result, nil
+	}
+	document, err := u.documentRepo.GetDocumentByCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if document == nil {
+		return result, nil
+	}
+	result.Valid = true
+	result.Type = document.Type
+	result.Title = document.Title
+	result.Details = document.Details
+	result.OwnerName = document.Owner.TitleName + document.Owner.FirstName + " " + document.Owner.LastName
+	result.StudentCode = document.Owner.Code
+	result.TemplateVersion = document.TemplateVersion
+	result.Checksum = document.Checksum
+	result.IssuedAt = document.IssuedAt
+	if file != nil {
+		sum := sha256.Sum256(file)
+		checksum := hex.EncodeToString(sum[:])
+		issue, err := u.documentRepo.GetIssueByChecksum(document.DocumentID, checksum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document issue: %w", err)
+		}
+		if issue != nil {
+			result.Details = issue.Details
+			result.TemplateVersion = issue.TemplateVersion
+			result.Checksum = issue.Checksum
+			result.IssuedAt = issue.IssuedAt
+		}
+		matches := issue != nil || checksum == document.Checksum
+		result.Matches = &matches
+	}
+	return result, nil
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import "errors"
+
+// ErrPermissionDenied ใช้เมื่อผู้ใช้ไม่มีสิทธิ์ในข้อมูลที่ร้องขอ (controller จะตอบกลับเป็น 403)
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrInvalidImport ใช้เมื่อไฟล์นำเข้าหรือตัวเลือกการนำเข้าไม่ถูกต้อง (controller จะตอบกลับเป็น 400)
+var ErrInvalidImport = errors.New("invalid import")
+
+// ErrInvalidRequest ใช้เมื่อค่าที่ร้องขอไม่ถูกต้อง (controller จะตอบกลับเป็น 400)
+var ErrInvalidRequest = errors.New("invalid request")
+
+// ErrNotFound ใช้เมื่อไม่พบข้อมูลที่ร้องขอ (controller จะตอบกลับเป็น 404)
+var ErrNotFound = errors.New("not found")
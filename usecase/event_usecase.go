@@ -7,6 +7,7 @@ import (
 
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 type EventUsecase interface {
@@ -23,6 +24,25 @@ type EventUsecase interface {
 	MyEvent(userID uint) ([]entities.EventResponse, error)
 
 	AllMyEventThisYear(userID uint,year uint) ([]entities.MyInside,[]entities.MyOutside,error)
+
+	HasEventPermission(eventID uint, userID uint, permission string) (bool, error)
+	SaveOrganizer(eventID uint, userID uint, req *OrganizerRequest) error
+	RemoveOrganizer(eventID uint, userID uint, organizerID uint) error
+	GetOrganizers(eventID uint, userID uint) ([]entities.EventOrganizer, error)
+}
+
+// สิทธิ์ของผู้จัดร่วม ผู้สร้างกิจกรรมมีทุกสิทธิ์
+const (
+	PermissionEdit          = "edit"
+	PermissionCertify       = "certify"
+	PermissionViewChecklist = "view_checklist"
+)
+
+type OrganizerRequest struct {
+	UserID           uint `json:"user_id"`
+	CanEdit          bool `json:"can_edit"`
+	CanCertify       bool `json:"can_certify"`
+	CanViewChecklist bool `json:"can_view_checklist"`
 }
 type Permission struct {
 	BranchIDs      string `json:"branches"`
@@ -41,6 +61,23 @@ type EventRequest struct {
 	Detail      string `json:"detail"`
 	Branches    []uint `json:"branches"`
 	Years       []uint `json:"years"`
+	CategoryID  *uint    `json:"category_id"`
+	Tags        []string `json:"tags"`
+	SDGs        []uint   `json:"sdgs"`
+}
+
+// EventFilter เงื่อนไขกรองรายการกิจกรรม ค่าว่างหรือ 0 คือไม่กรอง
+type EventFilter struct {
+	CategoryID uint
+	Tag        string
+	SDG        uint
+}
+
+// classification หมวดหมู่ แท็ก และ SDG ของกิจกรรมที่ตรวจแล้ว
+type classification struct {
+	CategoryID *uint
+	Tags       string
+	SDGs       string
 }
 
 type eventUsecase struct {
@@ -49,15 +86,23 @@ type eventUsecase struct {
 	insideRepo repository.EventInsideRepository
 	outsideRepo repository.OutsideRepository
 	studentRepo repository.StudentRepository
+	organizerRepo repository.OrganizerRepository
+	userRepo repository.UserRepository
+	feedbackRepo repository.FeedbackRepository
+	categoryRepo repository.CategoryRepository
 }
 
-func NewEventUsecase(eventRepo repository.EventRepository, branchRepo repository.BranchRepository, insideRepo repository.EventInsideRepository,outsideRepo repository.OutsideRepository,studentRepo repository.StudentRepository) EventUsecase {
+func NewEventUsecase(eventRepo repository.EventRepository, branchRepo repository.BranchRepository, insideRepo repository.EventInsideRepository,outsideRepo repository.OutsideRepository,studentRepo repository.StudentRepository,organizerRepo repository.OrganizerRepository,userRepo repository.UserRepository,feedbackRepo repository.FeedbackRepository,categoryRepo repository.CategoryRepository) EventUsecase {
 	return &eventUsecase{
 		eventRepo:  eventRepo,
 		branchRepo: branchRepo,
 		insideRepo: insideRepo,
 		outsideRepo: outsideRepo,
 		studentRepo: studentRepo,
+		organizerRepo: organizerRepo,
+		userRepo: userRepo,
+		feedbackRepo: feedbackRepo,
+		categoryRepo: categoryRepo,
 	}
 }
 
@@ -110,12 +155,37 @@ func (u *eventUsecase) validateBranches(branches []uint) error {
 	return nil
 }
 
+// classify ตรวจหมวดหมู่ แท็ก และ SDG ของคำขอ ถ้าไม่ระบุ SDG จะใช้ SDG ของหมวดหมู่
+func (u *eventUsecase) classify(req *EventRequest) (*classification, error) {
+	category, err := findCategory(u.categoryRepo, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := encodeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	sdgs, err := encodeSDGs(req.SDGs)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.SDGs) == 0 && category != nil && category.SDGs != "" {
+		sdgs = category.SDGs
+	}
+	return &classification{CategoryID: req.CategoryID, Tags: tags, SDGs: sdgs}, nil
+}
+
 func (u *eventUsecase) CreateEvent(req *EventRequest, userID uint) error {
 	permission, err := u.buildPermission(req.Branches, req.Years)
 	if err != nil {
 		return err
 	}
 
+	class, err := u.classify(req)
+	if err != nil {
+		return err
+	}
+
 	startDate, err := utility.ParseStartDate(req.StartDate)
 	if err != nil {
 		return err
@@ -134,6 +204,9 @@ func (u *eventUsecase) CreateEvent(req *EventRequest, userID uint) error {
 		AllowAllYear:   permission.AllowAllYear,
 		BranchIDs:      permission.BranchIDs,
 		Years:          permission.Years,
+		CategoryID:     class.CategoryID,
+		Tags:           class.Tags,
+		SDGs:           class.SDGs,
 	}
 
 	if err := u.eventRepo.CreateEvent(event) ;err != nil {
@@ -198,12 +271,14 @@ func (u *eventUsecase) AllAllowedEvent() ([]entities.EventResponse, error) {
 	return res, nil
 }
 
+// MyEvent กิจกรรมที่ผู้ใช้สร้างหรือร่วมจัด พร้อมคะแนนรวมจากผู้เข้าร่วม
 func (u *eventUsecase) MyEvent(userID uint) ([]entities.EventResponse, error) {
 	events, err := u.eventRepo.MyEvent(userID)
 	if err != nil {
 		return nil, err
 	}
 	var res []entities.EventResponse
+	var eventIDs []uint
 	for _, event := range events {
 		count, err := u.insideRepo.CountEventInside(event.EventID)
 		if err != nil {
@@ -214,6 +289,23 @@ func (u *eventUsecase) MyEvent(userID uint) ([]entities.EventResponse, error) {
 			return nil, err
 		}
 		res = append(res, *mappedEvent)
+		eventIDs = append(eventIDs, event.EventID)
+	}
+
+	summaries, err := u.feedbackRepo.Summaries(eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback summaries: %w", err)
+	}
+	ratings := make(map[uint]entities.FeedbackSummary, len(summaries))
+	for _, summary := range summaries {
+		ratings[summary.EventID] = summary
+	}
+	for i := range res {
+		rating, ok := ratings[res[i].EventID]
+		if !ok {
+			rating = entities.FeedbackSummary{EventID: res[i].EventID}
+		}
+		res[i].Rating = &rating
 	}
 	return res, nil
 }
@@ -256,7 +348,11 @@ func (u *eventUsecase) EditEvent(eventID uint, req *EventRequest, userID uint) e
 		return fmt.Errorf("event not found")
 	}
 
-	if event.Creator != userID {
+	allowed, err := u.hasPermission(event, userID, PermissionEdit)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		return fmt.Errorf("you do not have permission to edit this event")
 	}
 
@@ -274,6 +370,11 @@ func (u *eventUsecase) EditEvent(eventID uint, req *EventRequest, userID uint) e
 		return fmt.Errorf("failed to build permissions: %w", err)
 	}
 
+	class, err := u.classify(req)
+	if err != nil {
+		return err
+	}
+
 	event.EventName = req.EventName
 	event.StartDate = startDate
 	event.FreeSpace = req.FreeSpace
@@ -285,6 +386,9 @@ func (u *eventUsecase) EditEvent(eventID uint, req *EventRequest, userID uint) e
 	event.Years = permission.Years
 	event.AllowAllBranch = permission.AllowAllBranch
 	event.AllowAllYear = permission.AllowAllYear
+	event.CategoryID = class.CategoryID
+	event.Tags = class.Tags
+	event.SDGs = class.SDGs
 
 	return u.eventRepo.EditEvent(event)
 }
@@ -336,6 +440,19 @@ func mapEventResponse(event entities.Event, count uint) (*entities.EventResponse
 	}
 	limit := event.FreeSpace + count
 
+	tags, err := utility.DecodeStrings(event.Tags)
+	if err != nil {
+		return &entities.EventResponse{}, err
+	}
+	sdgs, err := utility.DecodeIDs(event.SDGs)
+	if err != nil {
+		return &entities.EventResponse{}, err
+	}
+	category, err := mapCategoryResponse(event.Category)
+	if err != nil {
+		return &entities.EventResponse{}, err
+	}
+
 	return &entities.EventResponse{
 		EventID:        event.EventID,
 		EventName:      event.EventName,
@@ -352,6 +469,9 @@ func mapEventResponse(event entities.Event, count uint) (*entities.EventResponse
 		Years:          years,
 		AllowAllBranch: event.AllowAllBranch,
 		AllowAllYear:   event.AllowAllYear,
+		Category:       category,
+		Tags:           tags,
+		SDGs:           sdgs,
 		Creator: struct {
 			UserID    uint   `json:"user_id"`
 			TitleName string `json:"title_name"`
@@ -376,12 +496,95 @@ func (u *eventUsecase) ToggleEventStatus(eventID uint, userID uint) error {
 	if err != nil {
 		return fmt.Errorf("event not found")
 	}
-	if event.Creator != userID {
+	allowed, err := u.hasPermission(event, userID, PermissionEdit)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		return fmt.Errorf("you do not have permission to edit this event")
 	}
 	return u.eventRepo.ToggleEventStatus(event.EventID)
 }
 
+func (u *eventUsecase) hasPermission(event *entities.Event, userID uint, permission string) (bool, error) {
+	if event.Creator == userID {
+		return true, nil
+	}
+	organizer, err := u.organizerRepo.GetOrganizer(event.EventID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check organizer: %w", err)
+	}
+	if organizer == nil {
+		return false, nil
+	}
+	switch permission {
+	case PermissionEdit:
+		return organizer.CanEdit, nil
+	case PermissionCertify:
+		return organizer.CanCertify, nil
+	case PermissionViewChecklist:
+		return organizer.CanViewChecklist, nil
+	}
+	return false, nil
+}
+
+// HasEventPermission ตรวจสอบว่าผู้ใช้เป็นผู้สร้าง หรือผู้จัดร่วมที่มีสิทธิ์ที่ระบุ
+func (u *eventUsecase) HasEventPermission(eventID uint, userID uint, permission string) (bool, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return false, fmt.Errorf("event not found")
+	}
+	return u.hasPermission(event, userID, permission)
+}
+
+// SaveOrganizer เพิ่มหรือแก้ไขสิทธิ์ผู้จัดร่วม ทำได้เฉพาะผู้สร้างกิจกรรม
+func (u *eventUsecase) SaveOrganizer(eventID uint, userID uint, req *OrganizerRequest) error {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found")
+	}
+	if event.Creator != userID {
+		return fmt.Errorf("%w: only the event creator can manage organizers", ErrPermissionDenied)
+	}
+	if req.UserID == event.Creator {
+		return fmt.Errorf("the creator is already the organizer of this event")
+	}
+	if _, err := u.userRepo.GetTeacherByUserID(req.UserID); err != nil {
+		return fmt.Errorf("teacher not found")
+	}
+	return u.organizerRepo.SaveOrganizer(&entities.EventOrganizer{
+		EventID:          eventID,
+		UserID:           req.UserID,
+		CanEdit:          req.CanEdit,
+		CanCertify:       req.CanCertify,
+		CanViewChecklist: req.CanViewChecklist,
+	})
+}
+
+func (u *eventUsecase) RemoveOrganizer(eventID uint, userID uint, organizerID uint) error {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found")
+	}
+	if event.Creator != userID {
+		return fmt.Errorf("%w: only the event creator can manage organizers", ErrPermissionDenied)
+	}
+	return u.organizerRepo.RemoveOrganizer(eventID, organizerID)
+}
+
+func (u *eventUsecase) GetOrganizers(eventID uint, userID uint) ([]entities.EventOrganizer, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+	if event.Creator != userID {
+		if organizer, err := u.organizerRepo.GetOrganizer(eventID, userID); err != nil || organizer == nil {
+			return nil, fmt.Errorf("%w: you are not an organizer of this event", ErrPermissionDenied)
+		}
+	}
+	return u.organizerRepo.GetOrganizers(eventID)
+}
+
 func (u *eventUsecase) AllMyEventThisYear(userID uint,year uint) ([]entities.MyInside,[]entities.MyOutside,error){
 	inside,err:= u.insideRepo.AllInsideThisYears(userID,year)
 	if err != nil {
@@ -398,8 +601,11 @@ func (u *eventUsecase) AllMyEventThisYear(userID uint,year uint) ([]entities.MyI
 			WorkingHour: event.Event.WorkingHour,
 			SchoolYear: event.Event.SchoolYear,
 			Status:event.Status,
+			Rejected: isRejected(event.Status, event.CertifiedAt),
 			Comment: event.Comment,
 			FilePDF: event.FilePDF,
+			CategoryID: event.Event.CategoryID,
+			CategoryName: categoryName(event.Event.Category),
 		}
 		insideEvents = append(insideEvents, mappedEvent)
 	}
@@ -409,19 +615,55 @@ func (u *eventUsecase) AllMyEventThisYear(userID uint,year uint) ([]entities.MyI
 	}
 	var outsideEvents []entities.MyOutside
 	for _, event := range outside {
-		mappedEvent := entities.MyOutside{
-			EventID: event.EventID,
-			EventName: event.EventName,
-			Location: event.Location,
-			StartDate: utility.FormatToThaiDate(event.StartDate),
-			StartTime: utility.FormatToThaiTime(event.StartDate),
-			WorkingHour: event.WorkingHour,
-			SchoolYear: event.SchoolYear,
-			Intendant: event.Intendant,
-			FilePDF: event.FilePDF,
-		}
-		outsideEvents = append(outsideEvents, mappedEvent)
+		outsideEvents = append(outsideEvents, mapMyOutside(event))
 	}
 
 	return insideEvents,outsideEvents,nil
+}
+
+func mapMyOutside(event entities.EventOutside) entities.MyOutside {
+	return entities.MyOutside{
+		EventID: event.EventID,
+		EventName: event.EventName,
+		Location: event.Location,
+		StartDate: utility.FormatToThaiDate(event.StartDate),
+		StartTime: utility.FormatToThaiTime(event.StartDate),
+		WorkingHour: event.WorkingHour,
+		SchoolYear: event.SchoolYear,
+		Intendant: event.Intendant,
+		FilePDF: event.FilePDF,
+		CategoryID: event.CategoryID,
+		CategoryName: categoryName(event.Category),
+	}
+}
+
+// FilterEvents กิจกรรมที่ตรงกับทุกเงื่อนไขใน filter แท็กเทียบแบบไม่สนตัวพิมพ์เล็กใหญ่
+func FilterEvents(events []entities.EventResponse, filter EventFilter) []entities.EventResponse {
+	if filter == (EventFilter{}) {
+		return events
+	}
+	tag := strings.ToLower(strings.TrimSpace(filter.Tag))
+	res := []entities.EventResponse{}
+	for _, event := range events {
+		if filter.CategoryID != 0 && (event.Category == nil || event.Category.CategoryID != filter.CategoryID) {
+			continue
+		}
+		if tag != "" && !containsString(event.Tags, tag) {
+			continue
+		}
+		if filter.SDG != 0 && !utility.ContainsUint(event.SDGs, filter.SDG) {
+			continue
+		}
+		res = append(res, event)
+	}
+	return res
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
\ No newline at end of file
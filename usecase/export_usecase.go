@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/utility"
+	"RESTAPI/utility/export"
+	"fmt"
+	"io"
+)
+
+// ExportFunc เขียนไฟล์ส่งออกลง w ถูกเรียกหลังตรวจสิทธิ์แล้ว ขณะส่ง response แบบ stream
+type ExportFunc func(w io.Writer) error
+
+// ExportUsecase ส่งออกรายชื่อและชั่วโมงกิจกรรมเป็น CSV หรือ XLSX
+type ExportUsecase interface {
+	ExportChecklist(eventID uint, userID uint, role string, staff bool, format string) (ExportFunc, string, error)
+	ExportParticipations(filter repository.ExportFilter, userID uint, role string, format string) (ExportFunc, string, error)
+	ExportStudentHours(filter repository.ExportFilter, userID uint, role string, format string) (ExportFunc, string, error)
+}
+
+type exportUsecase struct {
+	exportRepo         repository.ExportRepository
+	eventUsecase       EventUsecase
+	staffUsecase       StaffUsecase
+	requirementUsecase RequirementUsecase
+}
+
+func NewExportUsecase(exportRepo repository.ExportRepository, eventUsecase EventUsecase, staffUsecase StaffUsecase, requirementUsecase RequirementUsecase) ExportUsecase {
+	return &exportUsecase{
+		exportRepo:         exportRepo,
+		eventUsecase:       eventUsecase,
+		staffUsecase:       staffUsecase,
+		requirementUsecase: requirementUsecase,
+	}
+}
+
+// stream สร้าง ExportFunc ที่เขียนหัวตารางแล้วให้ rows เขียนข้อมูลทีละแถว
+// ถ้าเขียนไม่สำเร็จต้องปิด writer ด้วย Abort เพื่อคืนไฟล์ชั่วคราวของ xlsx
+func stream(format string, sheet string, header []interface{}, rows func(w export.Writer) error) ExportFunc {
+	return func(out io.Writer) error {
+		w, err := export.NewWriter(format, out, sheet)
+		if err != nil {
+			return err
+		}
+		closed := false
+		defer func() {
+			if !closed {
+				w.Abort()
+			}
+		}()
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := rows(w); err != nil {
+			return err
+		}
+		closed = true
+		return w.Close()
+	}
+}
+
+// scope ขอบเขตสาขาของผู้ส่งออก admin ไม่จำกัด (nil) ส่วนเจ้าหน้าที่คณะจำกัดเฉพาะสาขาในคณะ
+func (u *exportUsecase) scope(filter *repository.ExportFilter, userID uint, role string) error {
+	if role == "admin" {
+		return nil
+	}
+	branchIDs, err := u.staffUsecase.BranchIDs(userID)
+	if err != nil {
+		return err
+	}
+	if len(branchIDs) == 0 {
+		return fmt.Errorf("%w: you are not staff of any faculty", ErrPermissionDenied)
+	}
+	if filter.BranchID != 0 && !utility.ContainsUint(branchIDs, filter.BranchID) {
+		return fmt.Errorf("%w: branch is not in your faculty", ErrPermissionDenied)
+	}
+	filter.BranchIDs = branchIDs
+	return nil
+}
+
+func studentName(titleName string, firstName string, lastName string) string {
+	return titleName + firstName + " " + lastName
+}
+
+// ExportChecklist staff เป็น true เมื่อเรียกผ่านเส้นทางของเจ้าหน้าที่คณะ จะได้เฉพาะนักศึกษาในคณะ
+// นอกนั้นใช้สิทธิ์เดียวกับ MyChecklist
+func (u *exportUsecase) ExportChecklist(eventID uint, userID uint, role string, staff bool, format string) (ExportFunc, string, error) {
+	format, err := export.ValidFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+	var branchIDs []uint
+	if staff {
+		var filter repository.ExportFilter
+		if err := u.scope(&filter, userID, role); err != nil {
+			return nil, "", err
+		}
+		if filter.BranchIDs != nil {
+			event, err := u.eventUsecase.GetEventByID(eventID)
+			if err != nil {
+				return nil, "", fmt.Errorf("event not found")
+			}
+			owned, err := u.staffUsecase.OwnsEvent(userID, event)
+			if err != nil {
+				return nil, "", err
+			}
+			if !owned {
+				return nil, "", fmt.Errorf("%w: event is not in your faculty", ErrPermissionDenied)
+			}
+		}
+		branchIDs = filter.BranchIDs
+	} else if role != "admin" {
+		allowed, err := u.eventUsecase.HasEventPermission(eventID, userID, PermissionViewChecklist)
+		if err != nil {
+			return nil, "", err
+		}
+		if !allowed {
+			return nil, "", fmt.Errorf("%w: you cannot view the checklist of this event", ErrPermissionDenied)
+		}
+	}
+
+	header := []interface{}{"รหัสนักศึกษา", "ชื่อ-สกุล", "สาขา", "คณะ", "สถานะ", "ความคิดเห็น", "วันที่รับรอง", "ส่งหลักฐานแล้ว"}
+	write := stream(format, "checklist", header, func(w export.Writer) error {
+		return u.exportRepo.EachChecklist(eventID, branchIDs, func(row entities.ChecklistExport) error {
+			status := "รอรับรอง"
+			if row.Status {
+				status = "รับรองแล้ว"
+			} else if isRejected(row.Status, row.CertifiedAt) {
+				status = "ไม่ผ่านการรับรอง"
+			}
+			certifiedAt := ""
+			if row.CertifiedAt != nil {
+				certifiedAt = utility.FormatToThaiShortDate(*row.CertifiedAt)
+			}
+			hasFile := "ไม่"
+			if row.HasFile {
+				hasFile = "ใช่"
+			}
+			return w.Write([]interface{}{row.Code, studentName(row.TitleName, row.FirstName, row.LastName), row.BranchName, row.FacultyName, status, row.Comment, certifiedAt, hasFile})
+		})
+	})
+	return write, fmt.Sprintf("checklist_event_%d.%s", eventID, format), nil
+}
+
+// ExportParticipations การเข้าร่วมกิจกรรมทั้งหมดในปีการศึกษา กรองตามคณะหรือสาขาได้
+func (u *exportUsecase) ExportParticipations(filter repository.ExportFilter, userID uint, role string, format string) (ExportFunc, string, error) {
+	format, err := export.ValidFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := u.scope(&filter, userID, role); err != nil {
+		return nil, "", err
+	}
+	header := []interface{}{"รหัสนักศึกษา", "ชื่อ-สกุล", "สาขา", "คณะ", "ประเภท", "กิจกรรม", "หมวดหมู่", "วันที่", "ชั่วโมง", "สถานะ"}
+	write := stream(format, "participations", header, func(w export.Writer) error {
+		return u.exportRepo.EachParticipation(filter, func(row entities.ParticipationExport) error {
+			kind, status := "ภายใน", "รอรับรอง"
+			if row.Kind == entities.AttachmentOutside {
+				kind, status = "ภายนอก", "มีผู้รับรอง"
+			} else if row.Approved {
+				status = "รับรองแล้ว"
+			} else if row.Rejected {
+				status = "ไม่ผ่านการรับรอง"
+			}
+			category := row.CategoryName
+			if category == "" {
+				category = uncategorized
+			}
+			return w.Write([]interface{}{row.Code, studentName(row.TitleName, row.FirstName, row.LastName), row.BranchName, row.FacultyName, kind, row.EventName, category, utility.FormatToThaiShortDate(row.StartDate), row.WorkingHour, status})
+		})
+	})
+	return write, fmt.Sprintf("participations_%d.%s", filter.SchoolYear, format), nil
+}
+
+// ExportStudentHours ชั่วโมงรวมรายคนในปีการศึกษา พร้อมผลการประเมินตามเกณฑ์ที่ตรงกับคณะ สาขา และชั้นปีของนักศึกษา
+func (u *exportUsecase) ExportStudentHours(filter repository.ExportFilter, userID uint, role string, format string) (ExportFunc, string, error) {
+	format, err := export.ValidFormat(format)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := u.scope(&filter, userID, role); err != nil {
+		return nil, "", err
+	}
+	evaluate, err := u.requirementUsecase.Evaluator(filter.SchoolYear)
+	if err != nil {
+		return nil, "", err
+	}
+	header := []interface{}{"รหัสนักศึกษา", "ชื่อ-สกุล", "สาขา", "คณะ", "ชั่วโมงภายใน (รับรองแล้ว)", "ชั่วโมงภายใน (รอรับรอง)", "ชั่วโมงภายนอก", "รวม", "ชั่วโมงที่กำหนด", "ผลการประเมิน"}
+	write := stream(format, "hours", header, func(w export.Writer) error {
+		return u.exportRepo.EachStudentHours(filter, func(row entities.StudentHoursExport) error {
+			total := row.InsideHours + row.OutsideHours
+			var required interface{} = "-"
+			result := "-"
+			evaluation := evaluate(row.UserID, row.FacultyID, row.BranchID, row.ClassYear)
+			for _, rule := range evaluation.Rules {
+				if rule.Kind == entities.RequirementTotal {
+					required = rule.RequiredHours
+				}
+			}
+			if len(evaluation.Rules) > 0 {
+				result = "ไม่ผ่าน"
+				if evaluation.Met {
+					result = "ผ่าน"
+				}
+			}
+			return w.Write([]interface{}{row.Code, studentName(row.TitleName, row.FirstName, row.LastName), row.BranchName, row.FacultyName, row.InsideHours, row.PendingHours, row.OutsideHours, total, required, result})
+		})
+	})
+	return write, fmt.Sprintf("hours_%d.%s", filter.SchoolYear, format), nil
+}
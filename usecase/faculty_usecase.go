@@ -15,6 +15,8 @@ type FacultyUsecase interface {
 	GetFaculty(id uint) (*entities.Faculty, error) // ค้นหาคณะตาม ID
     DeleteFacultyByID(id uint) (*entities.Faculty, error)
 	AddFacultyStaff(facultyID uint,userID uint) error
+	RemoveFacultyStaff(facultyID uint, userID uint) error
+	GetFacultyStaff(facultyID uint) ([]entities.FacultyStaff, error)
 }
 
 // facultyUsecase struct ซึ่งจะใช้งาน repository ในการดึงข้อมูลจากฐานข้อมูล
@@ -71,6 +73,19 @@ func (u *facultyUsecase) AddFacultyStaff(facultyID uint,userID uint) error{
 	if err != nil {
 		return fmt.Errorf("user not found")
 	}
-	faculty.SuperUser=&teacher.UserID
-	return u.repo.AddFacultyStaff(faculty)
+	return u.repo.AddFacultyStaff(&entities.FacultyStaff{
+		FacultyID: faculty.FacultyID,
+		UserID:    teacher.UserID,
+	})
+}
+
+func (u *facultyUsecase) RemoveFacultyStaff(facultyID uint, userID uint) error {
+	return u.repo.RemoveFacultyStaff(facultyID, userID)
+}
+
+func (u *facultyUsecase) GetFacultyStaff(facultyID uint) ([]entities.FacultyStaff, error) {
+	if _, err := u.repo.GetFacultyByID(facultyID); err != nil {
+		return nil, fmt.Errorf("faculty not found")
+	}
+	return u.repo.GetFacultyStaff(facultyID)
 }
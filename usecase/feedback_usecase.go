@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ขีดจำกัดของความคิดเห็นและการแบ่งหน้า
+const (
+	maxFeedbackComment = 1000
+	maxFeedbackLimit   = 100
+)
+
+type FeedbackRequest struct {
+	Rating  uint   `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// ModerationRequest ซ่อนหรือเลิกซ่อนความคิดเห็น Reason บันทึกไว้สำหรับผู้ดูแลระบบ
+type ModerationRequest struct {
+	Hidden bool   `json:"hidden"`
+	Reason string `json:"reason"`
+}
+
+// FeedbackUsecase คะแนนและความคิดเห็นต่อกิจกรรมภายใน
+type FeedbackUsecase interface {
+	SubmitFeedback(eventID uint, userID uint, req *FeedbackRequest) (*entities.EventFeedback, error)
+	MyFeedback(eventID uint, userID uint) (*entities.EventFeedback, error)
+	EventFeedback(eventID uint, userID uint, role string) (*entities.EventFeedbackReport, error)
+	ListFeedback(filter repository.FeedbackFilter, page int) (*entities.FeedbackPage, error)
+	ModerateFeedback(feedbackID uint, adminID uint, req *ModerationRequest) (*entities.EventFeedback, error)
+}
+
+type feedbackUsecase struct {
+	feedbackRepo repository.FeedbackRepository
+	insideRepo   repository.EventInsideRepository
+	eventUsecase EventUsecase
+	editWindow   time.Duration
+}
+
+// NewFeedbackUsecase editWindow คือระยะเวลาหลังส่งครั้งแรกที่นักศึกษายังแก้ไขความคิดเห็นได้
+func NewFeedbackUsecase(feedbackRepo repository.FeedbackRepository, insideRepo repository.EventInsideRepository, eventUsecase EventUsecase, editWindow time.Duration) FeedbackUsecase {
+	return &feedbackUsecase{
+		feedbackRepo: feedbackRepo,
+		insideRepo:   insideRepo,
+		eventUsecase: eventUsecase,
+		editWindow:   editWindow,
+	}
+}
+
+// SubmitFeedback ส่งได้เฉพาะผู้ที่ได้รับการรับรองการเข้าร่วมแล้ว ส่งซ้ำคือแก้ไขรายการเดิมภายใน editWindow
+// การแก้ไขไม่ยกเลิกการซ่อนของผู้ดูแลระบบ
+func (u *feedbackUsecase) SubmitFeedback(eventID uint, userID uint, req *FeedbackRequest) (*entities.EventFeedback, error) {
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidRequest)
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(comment) > maxFeedbackComment {
+		return nil, fmt.Errorf("%w: comment must be at most %d characters", ErrInvalidRequest, maxFeedbackComment)
+	}
+
+	joined, err := u.insideRepo.IsUserJoinedEvent(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !joined {
+		return nil, fmt.Errorf("%w: you did not join this event", ErrPermissionDenied)
+	}
+	participation, err := u.insideRepo.GetParticipation(eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participation: %w", err)
+	}
+	if participation == nil {
+		return nil, fmt.Errorf("%w: you did not join this event", ErrPermissionDenied)
+	}
+	if !participation.Status {
+		return nil, fmt.Errorf("%w: your participation has not been approved", ErrPermissionDenied)
+	}
+
+	feedback, err := u.feedbackRepo.GetFeedback(eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+	if feedback == nil {
+		feedback = &entities.EventFeedback{EventID: eventID, UserID: userID}
+	} else if time.Since(feedback.CreatedAt) > u.editWindow {
+		return nil, fmt.Errorf("%w: feedback can no longer be edited", ErrPermissionDenied)
+	}
+	feedback.Rating = req.Rating
+	feedback.Comment = comment
+	if err := u.feedbackRepo.SaveFeedback(feedback); err != nil {
+		return nil, fmt.Errorf("failed to save feedback: %w", err)
+	}
+	return feedback, nil
+}
+
+func (u *feedbackUsecase) MyFeedback(eventID uint, userID uint) (*entities.EventFeedback, error) {
+	feedback, err := u.feedbackRepo.GetFeedback(eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+	if feedback == nil {
+		return nil, fmt.Errorf("%w: you have not given feedback for this event", ErrNotFound)
+	}
+	return feedback, nil
+}
+
+// EventFeedback สำหรับผู้จัดที่ดูรายชื่อผู้เข้าร่วมได้ ความคิดเห็นไม่ระบุตัวนักศึกษาและไม่รวมที่ถูกซ่อน
+func (u *feedbackUsecase) EventFeedback(eventID uint, userID uint, role string) (*entities.EventFeedbackReport, error) {
+	if role != "admin" {
+		allowed, err := u.eventUsecase.HasEventPermission(eventID, userID, PermissionViewChecklist)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: you cannot view feedback of this event", ErrPermissionDenied)
+		}
+	}
+	report := &entities.EventFeedbackReport{Summary: entities.FeedbackSummary{EventID: eventID}}
+	summaries, err := u.feedbackRepo.Summaries([]uint{eventID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback summary: %w", err)
+	}
+	if len(summaries) > 0 {
+		report.Summary = summaries[0]
+	}
+	if report.Comments, err = u.feedbackRepo.VisibleComments(eventID); err != nil {
+		return nil, fmt.Errorf("failed to get feedback comments: %w", err)
+	}
+	return report, nil
+}
+
+// ListFeedback page เริ่มที่ 1 filter.Limit อยู่ระหว่าง 1-100
+func (u *feedbackUsecase) ListFeedback(filter repository.FeedbackFilter, page int) (*entities.FeedbackPage, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page must be at least 1", ErrInvalidRequest)
+	}
+	if filter.Limit < 1 || filter.Limit > maxFeedbackLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidRequest, maxFeedbackLimit)
+	}
+	if filter.MaxRating > 5 {
+		return nil, fmt.Errorf("%w: max_rating must be between 1 and 5", ErrInvalidRequest)
+	}
+	filter.Offset = (page - 1) * filter.Limit
+	rows, total, err := u.feedbackRepo.ListFeedback(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	return &entities.FeedbackPage{Total: total, Page: page, Limit: filter.Limit, Rows: rows}, nil
+}
+
+func (u *feedbackUsecase) ModerateFeedback(feedbackID uint, adminID uint, req *ModerationRequest) (*entities.EventFeedback, error) {
+	feedback, err := u.feedbackRepo.GetFeedbackByID(feedbackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+	if feedback == nil {
+		return nil, fmt.Errorf("%w: feedback %d", ErrNotFound, feedbackID)
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(reason) > 255 {
+		return nil, fmt.Errorf("%w: reason must be at most 255 characters", ErrInvalidRequest)
+	}
+	feedback.Hidden = req.Hidden
+	feedback.HiddenBy, feedback.HiddenAt, feedback.HiddenReason = nil, nil, ""
+	if req.Hidden {
+		now := time.Now()
+		feedback.HiddenBy = &adminID
+		feedback.HiddenAt = &now
+		feedback.HiddenReason = reason
+	}
+	if err := u.feedbackRepo.SetHidden(feedback); err != nil {
+		return nil, fmt.Errorf("failed to moderate feedback: %w", err)
+	}
+	return feedback, nil
+}
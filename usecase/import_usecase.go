@@ -0,0 +1,660 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/domain/transaction"
+	"RESTAPI/infrastructure/mailer"
+	"RESTAPI/pkg"
+	"RESTAPI/utility/importer"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// importChunkSize จำนวนแถวต่อหนึ่ง transaction ในโหมด chunk
+const importChunkSize = 100
+
+// ImportOptions ตัวเลือกการนำเข้า Mode ว่างถือเป็น atomic
+type ImportOptions struct {
+	DryRun          bool
+	Mode            string
+	SendCredentials bool
+}
+
+// ImportUsecase นำเข้าข้อมูลจำนวนมากจากไฟล์ CSV/XLSX พร้อมรายงานผลรายแถว
+type ImportUsecase interface {
+	ImportStudents(fileName string, file io.Reader, options ImportOptions) (*entities.ImportReport, error)
+	ImportStructure(fileName string, file io.Reader, options ImportOptions) (*entities.ImportReport, error)
+	ImportTeachers(fileName string, file io.Reader, options ImportOptions) (*entities.ImportReport, error)
+}
+
+type importUsecase struct {
+	importRepo  repository.ImportRepository
+	facultyRepo repository.FacultyRepository
+	branchRepo  repository.BranchRepository
+	txManager   transaction.TransactionManager
+	mailer      mailer.Mailer
+	loginURL    string
+}
+
+// NewImportUsecase loginURL คือลิงก์เข้าสู่ระบบที่แนบในอีเมลแจ้งรหัสผ่าน
+func NewImportUsecase(importRepo repository.ImportRepository, facultyRepo repository.FacultyRepository, branchRepo repository.BranchRepository, txManager transaction.TransactionManager, mailer mailer.Mailer, loginURL string) ImportUsecase {
+	return &importUsecase{
+		importRepo:  importRepo,
+		facultyRepo: facultyRepo,
+		branchRepo:  branchRepo,
+		txManager:   txManager,
+		mailer:      mailer,
+		loginURL:    loginURL,
+	}
+}
+
+// importOp การบันทึกของแถวหนึ่ง สถานะเมื่อสำเร็จดูจาก result.Action
+// credentials มีค่าเมื่อเป็นผู้ใช้ใหม่ที่ต้องแจ้งรหัสผ่าน
+type importOp struct {
+	result      *entities.ImportRowResult
+	apply       func(tx transaction.Transaction) error
+	credentials *importCredentials
+}
+
+// importCredentials รหัสผ่านเริ่มต้นของผู้ใช้ใหม่ generated เป็น true เมื่อระบบสุ่มให้
+type importCredentials struct {
+	user      *entities.User
+	name      string
+	password  string
+	generated bool
+}
+
+// readImport อ่านไฟล์และตรวจตัวเลือก ข้อผิดพลาดทั้งหมดห่อ ErrInvalidImport
+func (u *importUsecase) readImport(fileName string, file io.Reader, options *ImportOptions, columns ...string) ([]importer.Row, error) {
+	switch options.Mode {
+	case "":
+		options.Mode = entities.ImportAtomic
+	case entities.ImportAtomic, entities.ImportChunk:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidImport, options.Mode)
+	}
+	if options.SendCredentials && !u.mailer.Enabled() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, mailer.ErrNotConfigured)
+	}
+	rows, err := importer.ReadRows(fileName, file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if err := importer.Require(rows, columns...); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	return rows, nil
+}
+
+// uniqueIn ตรวจค่าซ้ำภายในไฟล์ คืนข้อความผิดพลาดถ้าค่านี้เคยพบในแถวก่อนหน้า
+type uniqueIn map[string]int
+
+func (seen uniqueIn) check(label string, value string, line int) string {
+	key := strings.ToLower(value)
+	if first, ok := seen[key]; ok {
+		return fmt.Sprintf("duplicate %s in file (row %d)", label, first)
+	}
+	seen[key] = line
+	return ""
+}
+
+// fail เพิ่มข้อผิดพลาดให้แถว
+func fail(result *entities.ImportRowResult, format string, args ...interface{}) {
+	result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+}
+
+// requireColumns ทุกคอลัมน์ใน columns ต้องมีค่า
+func requireColumns(result *entities.ImportRowResult, row importer.Row, columns ...string) {
+	for _, column := range columns {
+		if row.Get(column) == "" {
+			fail(result, "%s is required", column)
+		}
+	}
+}
+
+// checkUnique ค่าต้องไม่ซ้ำกับแถวก่อนหน้าและไม่ซ้ำกับฐานข้อมูล ยกเว้นเป็นค่าเดิมของข้อมูลที่กำลังแก้ไข (own)
+func checkUnique(result *entities.ImportRowResult, label string, value string, line int, seen uniqueIn, existing map[string]bool, own string) {
+	if value == "" {
+		return
+	}
+	if msg := seen.check(label, value, line); msg != "" {
+		fail(result, "%s", msg)
+	} else if existing[strings.ToLower(value)] && !strings.EqualFold(value, own) {
+		fail(result, "%s %s already exists", label, value)
+	}
+}
+
+// rowPassword รหัสผ่านจากคอลัมน์ password ถ้าว่างระบบสุ่มให้
+func rowPassword(result *entities.ImportRowResult, row importer.Row) (string, bool, error) {
+	password := row.Get("password")
+	if password != "" {
+		if len(password) < 8 {
+			fail(result, "password must be at least 8 characters")
+		}
+		return password, false, nil
+	}
+	password, err := pkg.GeneratePassword(10)
+	return password, true, err
+}
+
+// existingSet ค่าที่มีอยู่แล้วในฐานข้อมูล เทียบแบบไม่สนตัวพิมพ์เล็กใหญ่
+func existingSet(values []string, lookup func([]string) ([]string, error)) (map[string]bool, error) {
+	if len(values) == 0 {
+		return map[string]bool{}, nil
+	}
+	found, err := lookup(values)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(found))
+	for _, value := range found {
+		set[strings.ToLower(value)] = true
+	}
+	return set, nil
+}
+
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+// validPhone เบอร์โทรศัพท์ตัวเลข 9-10 หลัก
+func validPhone(phone string) bool {
+	if len(phone) < 9 || len(phone) > 10 {
+		return false
+	}
+	for _, c := range phone {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// newReport สร้างรายงานหนึ่งแถวต่อหนึ่งแถวข้อมูล key คือคอลัมน์ที่ใช้อ้างอิง
+func newReport(rows []importer.Row, key string, options ImportOptions) *entities.ImportReport {
+	report := &entities.ImportReport{
+		DryRun: options.DryRun,
+		Mode:   options.Mode,
+		Total:  len(rows),
+		Rows:   make([]entities.ImportRowResult, len(rows)),
+	}
+	for i, row := range rows {
+		report.Rows[i] = entities.ImportRowResult{Row: row.Line, Key: row.Get(key)}
+	}
+	return report
+}
+
+// finishValidation นับแถวที่ถูกต้อง ถ้าเป็น dry-run หรือ atomic ที่มีแถวผิดจะไม่บันทึก (คืน false)
+func finishValidation(report *entities.ImportReport) bool {
+	for i := range report.Rows {
+		if len(report.Rows[i].Errors) > 0 {
+			report.Rows[i].Status = entities.ImportInvalid
+			report.Invalid++
+		} else {
+			report.Rows[i].Status = entities.ImportValid
+			report.Valid++
+		}
+	}
+	if report.DryRun {
+		return false
+	}
+	if report.Mode == entities.ImportAtomic && report.Invalid > 0 {
+		for i := range report.Rows {
+			if report.Rows[i].Status == entities.ImportValid {
+				report.Rows[i].Status = entities.ImportSkipped
+				report.Skipped++
+			}
+		}
+		return false
+	}
+	return report.Valid > 0
+}
+
+// apply บันทึกแถวที่ผ่านการตรวจ atomic บันทึกทั้งหมดใน transaction เดียว chunk บันทึกทีละชุด
+// ชุดที่บันทึกไม่สำเร็จจะเป็น failed ทั้งชุด แถวที่ไม่มีอะไรเปลี่ยนเป็น skipped
+// จากนั้นแจ้งรหัสผ่านของผู้ใช้ใหม่ถ้าเลือกไว้
+func (u *importUsecase) apply(report *entities.ImportReport, ops []importOp, options ImportOptions) error {
+	var pending []importOp
+	for _, op := range ops {
+		if op.result.Status != entities.ImportValid {
+			continue
+		}
+		if op.result.Action == entities.ImportActionNone {
+			op.result.Status = entities.ImportSkipped
+			report.Skipped++
+			continue
+		}
+		if op.credentials != nil {
+			hashed, err := pkg.HashPassword(op.credentials.password)
+			if err != nil {
+				return err
+			}
+			op.credentials.user.Password = hashed
+		}
+		pending = append(pending, op)
+	}
+
+	size := importChunkSize
+	if options.Mode == entities.ImportAtomic {
+		size = len(pending)
+	}
+	for start := 0; start < len(pending); start += size {
+		end := start + size
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := pending[start:end]
+		tx := u.txManager.Begin()
+		var err error
+		for _, op := range chunk {
+			if err = op.apply(tx); err != nil {
+				err = fmt.Errorf("row %d: %w", op.result.Row, err)
+				break
+			}
+		}
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			tx.Rollback()
+		}
+		for _, op := range chunk {
+			if err != nil {
+				op.result.Status = entities.ImportFailed
+				op.result.Errors = append(op.result.Errors, err.Error())
+				report.Failed++
+				continue
+			}
+			if op.result.Action == entities.ImportActionCreate {
+				op.result.Status = entities.ImportCreated
+				report.Created++
+			} else {
+				op.result.Status = entities.ImportUpdated
+				report.Updated++
+			}
+			if op.credentials != nil {
+				u.deliverCredentials(op.result, op.credentials, options)
+			}
+		}
+	}
+	return nil
+}
+
+// deliverCredentials ส่งรหัสผ่านทางอีเมล ถ้าไม่ได้ส่งหรือส่งไม่สำเร็จจะแสดงรหัสผ่านที่สุ่มไว้ในรายงานแทน
+func (u *importUsecase) deliverCredentials(result *entities.ImportRowResult, credentials *importCredentials, options ImportOptions) {
+	if options.SendCredentials {
+		body := fmt.Sprintf("เรียน %s\n\nระบบได้สร้างบัญชีผู้ใช้ของคุณแล้ว\nอีเมล: %s\nรหัสผ่านเริ่มต้น: %s\nเข้าสู่ระบบได้ที่ %s\n\nกรุณาเก็บรหัสผ่านนี้เป็นความลับ",
+			credentials.name, credentials.user.Email, credentials.password, u.loginURL)
+		if err := u.mailer.Send(credentials.user.Email, "บัญชีผู้ใช้ระบบกิจกรรมนักศึกษา", body); err != nil {
+			result.EmailError = err.Error()
+		} else {
+			result.EmailSent = true
+			return
+		}
+	}
+	if credentials.generated {
+		result.Password = credentials.password
+	}
+}
+
+// ImportStudents คอลัมน์ code, title_name, first_name, last_name, email, phone, branch_code, year
+// และ password (ไม่บังคับ ถ้าว่างระบบสุ่มให้)
+func (u *importUsecase) ImportStudents(fileName string, file io.Reader, options ImportOptions) (*entities.ImportReport, error) {
+	rows, err := u.readImport(fileName, file, &options, "code", "title_name", "first_name", "last_name", "email", "phone", "branch_code", "year")
+	if err != nil {
+		return nil, err
+	}
+	branches, err := u.branchRepo.GetAllBranches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+	branchIDs := make(map[string]uint, len(branches))
+	for _, branch := range branches {
+		branchIDs[strings.ToLower(branch.BranchCode)] = branch.BranchID
+	}
+
+	var codes, emails, phones []string
+	for _, row := range rows {
+		codes = append(codes, row.Get("code"))
+		emails = append(emails, row.Get("email"))
+		phones = append(phones, row.Get("phone"))
+	}
+	existingCodes, err := existingSet(codes, u.importRepo.ExistingStudentCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check student codes: %w", err)
+	}
+	existingEmails, err := existingSet(emails, u.importRepo.ExistingEmails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check emails: %w", err)
+	}
+	existingPhones, err := existingSet(phones, u.importRepo.ExistingStudentPhones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check phones: %w", err)
+	}
+
+	report := newReport(rows, "code", options)
+	seenCodes, seenEmails, seenPhones := uniqueIn{}, uniqueIn{}, uniqueIn{}
+	ops := make([]importOp, len(rows))
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Action = entities.ImportActionCreate
+		requireColumns(result, row, "code", "title_name", "first_name", "last_name", "email", "phone", "branch_code", "year")
+		code, email, phone := row.Get("code"), row.Get("email"), row.Get("phone")
+		checkUnique(result, "code", code, row.Line, seenCodes, existingCodes, "")
+		if email != "" && !validEmail(email) {
+			fail(result, "invalid email %q", email)
+		} else {
+			checkUnique(result, "email", email, row.Line, seenEmails, existingEmails, "")
+		}
+		if phone != "" && !validPhone(phone) {
+			fail(result, "invalid phone %q", phone)
+		} else {
+			checkUnique(result, "phone", phone, row.Line, seenPhones, existingPhones, "")
+		}
+		branchID, ok := branchIDs[strings.ToLower(row.Get("branch_code"))]
+		if row.Get("branch_code") != "" && !ok {
+			fail(result, "unknown branch_code %s", row.Get("branch_code"))
+		}
+		year, err := strconv.Atoi(row.Get("year"))
+		if row.Get("year") != "" && (err != nil || year < 1 || year > 8) {
+			fail(result, "invalid year %q", row.Get("year"))
+		}
+		password, generated, err := rowPassword(result, row)
+		if err != nil {
+			return nil, err
+		}
+
+		user := &entities.User{
+			Email: email,
+			Role:  "student",
+			Student: &entities.Student{
+				TitleName: row.Get("title_name"),
+				FirstName: row.Get("first_name"),
+				LastName:  row.Get("last_name"),
+				Phone:     phone,
+				Code:      code,
+				Year:      uint(year),
+				BranchId:  branchID,
+			},
+		}
+		ops[i] = importOp{
+			result: result,
+			apply: func(tx transaction.Transaction) error {
+				return u.importRepo.CreateUsers(tx, []entities.User{*user})
+			},
+			credentials: &importCredentials{
+				user:      user,
+				name:      row.Get("title_name") + row.Get("first_name") + " " + row.Get("last_name"),
+				password:  password,
+				generated: generated,
+			},
+		}
+	}
+
+	if !finishValidation(report) {
+		return report, nil
+	}
+	if err := u.apply(report, ops, options); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ImportStructure โครงสร้างคณะและสาขา คอลัมน์ faculty_code, faculty_name, branch_code, branch_name
+// หนึ่งแถวต่อหนึ่งสาขา (branch_code ว่างได้ถ้าต้องการเพิ่มเฉพาะคณะ) อ้างอิงข้อมูลเดิมด้วยรหัส
+// ชื่อที่ว่างจะใช้ชื่อเดิม ถ้ารหัสมีอยู่แล้วและชื่อตรงกันจะข้ามแถวนั้น
+func (u *importUsecase) ImportStructure(fileName string, file io.Reader, options ImportOptions) (*entities.ImportReport, error) {
+	rows, err := u.readImport(fileName, file, &options, "faculty_code", "faculty_name", "branch_code", "branch_name")
+	if err != nil {
+		return nil, err
+	}
+	faculties, err := u.facultyRepo.GetAllFaculties()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faculties: %w", err)
+	}
+	branches, err := u.branchRepo.GetAllBranches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+	// ชื่อคณะและสาขาห้ามซ้ำ เก็บว่าชื่อไหนเป็นของรหัสใด
+	facultyByCode := map[string]*entities.Faculty{}
+	facultyNames := map[string]string{}
+	for i := range faculties {
+		facultyByCode[strings.ToLower(faculties[i].FacultyCode)] = &faculties[i]
+		facultyNames[strings.ToLower(faculties[i].FacultyName)] = faculties[i].FacultyCode
+	}
+	branchByCode := map[string]*entities.Branch{}
+	branchNames := map[string]string{}
+	for i := range branches {
+		branches[i].Faculty = entities.Faculty{}
+		branchByCode[strings.ToLower(branches[i].BranchCode)] = &branches[i]
+		branchNames[strings.ToLower(branches[i].BranchName)] = branches[i].BranchCode
+	}
+
+	report := newReport(rows, "branch_code", options)
+	facultyRows := map[string]int{}
+	seenBranches := uniqueIn{}
+	ops := make([]importOp, len(rows))
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Action = entities.ImportActionNone
+		ops[i].result = result
+		if result.Key == "" {
+			result.Key = row.Get("faculty_code")
+		}
+		requireColumns(result, row, "faculty_code")
+		facultyCode, facultyName := row.Get("faculty_code"), row.Get("faculty_name")
+		branchCode, branchName := row.Get("branch_code"), row.Get("branch_name")
+		if facultyCode == "" {
+			continue
+		}
+
+		// คณะบันทึกในแถวแรกที่พบรหัสนั้น แถวถัดไปต้องใช้ชื่อเดียวกันหรือเว้นว่าง
+		faculty, saveFaculty := facultyByCode[strings.ToLower(facultyCode)], false
+		if first, ok := facultyRows[strings.ToLower(facultyCode)]; ok {
+			if facultyName != "" && facultyName != faculty.FacultyName {
+				fail(result, "faculty_name differs from row %d", first)
+			}
+		} else {
+			facultyRows[strings.ToLower(facultyCode)] = row.Line
+			if faculty == nil {
+				if facultyName == "" {
+					fail(result, "faculty_name is required for new faculty %s", facultyCode)
+				}
+				faculty = &entities.Faculty{FacultyCode: facultyCode}
+				facultyByCode[strings.ToLower(facultyCode)] = faculty
+				result.Action = entities.ImportActionCreate
+				saveFaculty = true
+			} else if facultyName != "" && facultyName != faculty.FacultyName {
+				result.Action = entities.ImportActionUpdate
+				saveFaculty = true
+			}
+			if saveFaculty && facultyName != "" {
+				if owner, ok := facultyNames[strings.ToLower(facultyName)]; ok && !strings.EqualFold(owner, facultyCode) {
+					fail(result, "faculty_name %s already used by faculty %s", facultyName, owner)
+				}
+				facultyNames[strings.ToLower(facultyName)] = facultyCode
+				faculty.FacultyName = facultyName
+			}
+		}
+
+		var branch *entities.Branch
+		saveBranch := false
+		if branchCode != "" {
+			if msg := seenBranches.check("branch_code", branchCode, row.Line); msg != "" {
+				fail(result, "%s", msg)
+			}
+			branch = branchByCode[strings.ToLower(branchCode)]
+			if branch == nil {
+				if branchName == "" {
+					fail(result, "branch_name is required for new branch %s", branchCode)
+				}
+				branch = &entities.Branch{BranchCode: branchCode}
+				result.Action = entities.ImportActionCreate
+				saveBranch = true
+			} else if (branchName != "" && branchName != branch.BranchName) || branch.FacultyId != faculty.FacultyID || faculty.FacultyID == 0 {
+				if result.Action == entities.ImportActionNone {
+					result.Action = entities.ImportActionUpdate
+				}
+				saveBranch = true
+			}
+			if saveBranch && branchName != "" {
+				if owner, ok := branchNames[strings.ToLower(branchName)]; ok && !strings.EqualFold(owner, branchCode) {
+					fail(result, "branch_name %s already used by branch %s", branchName, owner)
+				}
+				branchNames[strings.ToLower(branchName)] = branchCode
+				branch.BranchName = branchName
+			}
+		} else if branchName != "" {
+			fail(result, "branch_code is required when branch_name is given")
+		}
+
+		ops[i] = importOp{
+			result: result,
+			apply: func(tx transaction.Transaction) error {
+				if saveFaculty {
+					if err := u.importRepo.SaveFaculty(tx, faculty); err != nil {
+						return err
+					}
+				}
+				if saveBranch {
+					if faculty.FacultyID == 0 {
+						return fmt.Errorf("faculty %s was not saved", faculty.FacultyCode)
+					}
+					branch.FacultyId = faculty.FacultyID
+					return u.importRepo.SaveBranch(tx, branch)
+				}
+				return nil
+			},
+		}
+	}
+
+	if !finishValidation(report) {
+		return report, nil
+	}
+	if err := u.apply(report, ops, options); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ImportTeachers รายชื่ออาจารย์ คอลัมน์ code, title_name, first_name, last_name, email, phone
+// และ password (ไม่บังคับ) อ้างอิงอาจารย์เดิมด้วยรหัส ถ้ามีอยู่แล้วจะแก้ไขชื่อ อีเมลและเบอร์โทร
+// (ไม่เปลี่ยนรหัสผ่านและ role) ถ้าไม่มีอะไรเปลี่ยนจะข้ามแถวนั้น อาจารย์ใหม่ได้ role teacher
+func (u *importUsecase) ImportTeachers(fileName string, file io.Reader, options ImportOptions) (*entities.ImportReport, error) {
+	rows, err := u.readImport(fileName, file, &options, "code", "title_name", "first_name", "last_name", "email", "phone")
+	if err != nil {
+		return nil, err
+	}
+	var codes, emails, phones []string
+	for _, row := range rows {
+		codes = append(codes, row.Get("code"))
+		emails = append(emails, row.Get("email"))
+		phones = append(phones, row.Get("phone"))
+	}
+	existingUsers, err := u.importRepo.GetTeachersByCodes(codes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teachers: %w", err)
+	}
+	teachers := map[string][]entities.User{}
+	for _, user := range existingUsers {
+		key := strings.ToLower(user.Teacher.Code)
+		teachers[key] = append(teachers[key], user)
+	}
+	existingEmails, err := existingSet(emails, u.importRepo.ExistingEmails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check emails: %w", err)
+	}
+	existingPhones, err := existingSet(phones, u.importRepo.ExistingTeacherPhones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check phones: %w", err)
+	}
+
+	report := newReport(rows, "code", options)
+	seenCodes, seenEmails, seenPhones := uniqueIn{}, uniqueIn{}, uniqueIn{}
+	ops := make([]importOp, len(rows))
+	for i, row := range rows {
+		result := &report.Rows[i]
+		requireColumns(result, row, "code", "title_name", "first_name", "last_name", "email", "phone")
+		code, email, phone := row.Get("code"), row.Get("email"), row.Get("phone")
+		if msg := seenCodes.check("code", code, row.Line); code != "" && msg != "" {
+			fail(result, "%s", msg)
+		}
+		matches := teachers[strings.ToLower(code)]
+		if len(matches) > 1 {
+			fail(result, "code %s matches %d teachers", code, len(matches))
+		}
+		var existing *entities.User
+		if len(matches) == 1 {
+			existing = &matches[0]
+		}
+		ownEmail, ownPhone := "", ""
+		if existing != nil {
+			ownEmail, ownPhone = existing.Email, existing.Teacher.Phone
+		}
+		if email != "" && !validEmail(email) {
+			fail(result, "invalid email %q", email)
+		} else {
+			checkUnique(result, "email", email, row.Line, seenEmails, existingEmails, ownEmail)
+		}
+		if phone != "" && !validPhone(phone) {
+			fail(result, "invalid phone %q", phone)
+		} else {
+			checkUnique(result, "phone", phone, row.Line, seenPhones, existingPhones, ownPhone)
+		}
+
+		teacher := entities.Teacher{
+			TitleName: row.Get("title_name"),
+			FirstName: row.Get("first_name"),
+			LastName:  row.Get("last_name"),
+			Phone:     phone,
+			Code:      code,
+		}
+		if existing != nil {
+			teacher.UserID = existing.UserID
+			user := &entities.User{UserID: existing.UserID, Email: email, Teacher: &teacher}
+			result.Action = entities.ImportActionNone
+			if teacher != *existing.Teacher || email != existing.Email {
+				result.Action = entities.ImportActionUpdate
+			}
+			ops[i] = importOp{
+				result: result,
+				apply: func(tx transaction.Transaction) error {
+					return u.importRepo.UpdateTeacher(tx, user)
+				},
+			}
+			continue
+		}
+
+		result.Action = entities.ImportActionCreate
+		password, generated, err := rowPassword(result, row)
+		if err != nil {
+			return nil, err
+		}
+		user := &entities.User{Email: email, Role: "teacher", Teacher: &teacher}
+		ops[i] = importOp{
+			result: result,
+			apply: func(tx transaction.Transaction) error {
+				return u.importRepo.CreateUsers(tx, []entities.User{*user})
+			},
+			credentials: &importCredentials{
+				user:      user,
+				name:      teacher.TitleName + teacher.FirstName + " " + teacher.LastName,
+				password:  password,
+				generated: generated,
+			},
+		}
+	}
+
+	if !finishValidation(report) {
+		return report, nil
+	}
+	if err := u.apply(report, ops, options); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
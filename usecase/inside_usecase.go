@@ -4,39 +4,62 @@ import (
 	"RESTAPI/domain/entities"
 	"RESTAPI/domain/repository"
 	"RESTAPI/domain/transaction"
-	// "RESTAPI/utility"
+	"RESTAPI/infrastructure/storage"
+	"RESTAPI/utility"
 	"RESTAPI/utility/fileSystem"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
-	"os"
+	"sort"
+	"time"
 )
 
 
 type EventInsideUsecase interface{
 	JoinEventInside(eventID uint, userID uint) error
 	UnJoinEventInside(eventID uint , userID uint) error
-	UpdateEventStatusAndComment(eventID uint, userID uint, status bool, comment string) error
+	UpdateEventStatusAndComment(eventID uint, userID uint, certifierID uint, role string, status bool, comment string) error
+	BulkCertify(eventID uint, certifierID uint, role string, staff bool, req *entities.BulkCertifyRequest) (*entities.BulkCertifyReport, error)
 	CountEventInside(eventID uint) (uint,error)
 	UploadFile(file *multipart.FileHeader, eventID uint, userID uint) error 	
-	GetFile(eventID uint,userID uint) (string,error)
-    MyChecklist(userID uint,eventID uint) ([]entities.MyChecklist,error)
+	GetFile(eventID uint,userID uint) (io.ReadCloser,error)
+	GetFileFor(eventID uint, ownerID uint, requesterID uint, role string) (io.ReadCloser, error)
+	FileKey(eventID uint, ownerID uint) (string, error)
+	GetFileByKey(eventID uint, ownerID uint, fileKey string) (io.ReadCloser, error)
+	CanViewFile(eventID uint, ownerID uint, requesterID uint, role string) error
+	GetFileVersions(eventID uint, ownerID uint, requesterID uint, role string) ([]entities.EvidenceVersion, error)
+	GetFileVersion(versionID uint, requesterID uint, role string) (io.ReadCloser, error)
+    MyChecklist(userID uint, role string, eventID uint) ([]entities.MyChecklist,error)
+	AttendanceSheet(userID uint, role string, eventID uint) ([]byte, string, error)
 
 }
 
 type eventInsideUsecase struct{
 	insideRepo repository.EventInsideRepository
+	evidenceRepo repository.EvidenceRepository
 	userRepo repository.UserRepository
 	eventUsecase EventUsecase
+	staffUsecase StaffUsecase
 	txManager transaction.TransactionManager
-	
+	store storage.Storage
+	validator *filesystem.PDFValidator
+	renderer *filesystem.PDFRenderer
 }
 
-func NewEventInsideUsecase(insideRepo repository.EventInsideRepository,userRepo repository.UserRepository,eventUsecase EventUsecase,txManager transaction.TransactionManager) EventInsideUsecase{
+func NewEventInsideUsecase(insideRepo repository.EventInsideRepository,evidenceRepo repository.EvidenceRepository,userRepo repository.UserRepository,eventUsecase EventUsecase,staffUsecase StaffUsecase,txManager transaction.TransactionManager,store storage.Storage,validator *filesystem.PDFValidator,renderer *filesystem.PDFRenderer) EventInsideUsecase{
 	return &eventInsideUsecase{
 		insideRepo: insideRepo,
+		evidenceRepo: evidenceRepo,
 		userRepo: userRepo,
 		eventUsecase: eventUsecase,
+		staffUsecase: staffUsecase,
 		txManager: txManager,
+		store: store,
+		validator: validator,
+		renderer: renderer,
 	}
 }
 
@@ -91,11 +114,11 @@ func (u *eventInsideUsecase) JoinEventInside(eventID uint, userID uint) error {
         return fmt.Errorf("user is not allowed to join this event")
     }
 
+    // ผู้รับรองจะถูกบันทึกเมื่อมีการรับรองจริง
     eventInside := &entities.EventInside{
-        EventId:   eventID,
-        User:      userID,
-        Status:    false,
-        Certifier: event.Creator.UserID,
+        EventId: eventID,
+        User:    userID,
+        Status:  false,
     }
 
     err = u.insideRepo.JoinEventInside(eventInside, u.txManager)
@@ -132,73 +155,414 @@ func (u *eventInsideUsecase) UnJoinEventInside(eventID uint, userID uint) error
 }
 
 func (u *eventInsideUsecase) UploadFile(file *multipart.FileHeader, eventID uint, userID uint) error {
-    if file.Header.Get("Content-Type") != "application/pdf" {
-        return fmt.Errorf("only PDF files are allowed")
+    // ตรวจจากเนื้อหาไฟล์จริง ไม่เชื่อ Content-Type ที่ client ส่งมา
+    if file.Size > u.validator.MaxSize {
+        return &filesystem.ValidationError{Code: filesystem.CodeFileTooLarge, Message: fmt.Sprintf("file exceeds the maximum size of %d bytes", u.validator.MaxSize)}
     }
-    // ค้นหาว่า event นี้มีการบันทึกไฟล์ไว้หรือยัง
-    currentFilePath, err := u.insideRepo.GetFilePathByEvent(eventID, userID)
+    src, err := file.Open()
     if err != nil {
-        return fmt.Errorf("failed to fetch current file path: %w", err)
+        return fmt.Errorf("failed to open file: %w", err)
     }
-
-    // ถ้ามีไฟล์เก่าอยู่ ให้ลบไฟล์เก่าก่อน 
-    if currentFilePath != "" {
-        removeErr := os.Remove(currentFilePath)
-        if removeErr != nil {
-            return fmt.Errorf("failed to remove old file: %v", removeErr)
-        }
+    data, err := u.validator.ReadAndValidate(src)
+    src.Close()
+    if err != nil {
+        return err
+    }
+    // ตรวจว่าผู้ใช้เข้าร่วม event นี้อยู่จริง และหลักฐานยังไม่ได้รับการรับรอง
+    inside, err := u.insideRepo.GetParticipation(eventID, userID)
+    if err != nil {
+        return fmt.Errorf("failed to fetch participation: %w", err)
+    }
+    if inside == nil {
+        return fmt.Errorf("%w: you did not join this event", ErrNotFound)
+    }
+    if inside.Status {
+        return fmt.Errorf("%w: approved evidence cannot be replaced", ErrPermissionDenied)
     }
-    // บันทึกไฟล์ใหม่
-    path, err := filesystem.SaveFile(file, userID)
+
+    // บันทึกไฟล์ใหม่ให้เสร็จก่อน แล้วค่อยสลับ file_pdf ใน transaction ไฟล์เดิมเก็บไว้เป็นเวอร์ชันก่อนหน้า
+    path, err := filesystem.SaveFile(u.store, data, userID)
     if err != nil {
         return fmt.Errorf("failed to save file: %w", err)
     }
-    // อัปเดตฐานข้อมูลด้วย path ใหม่
-    err = u.insideRepo.UpdateFile(eventID, userID, path)
+    sum := sha256.Sum256(data)
+    version := &entities.EvidenceVersion{
+        EventID:  eventID,
+        UserID:   userID,
+        FileKey:  path,
+        Size:     int64(len(data)),
+        Checksum: hex.EncodeToString(sum[:]),
+    }
+    err = u.insideRepo.ReplaceFile(version, u.txManager)
     if err != nil {
-        removeErr := os.Remove(path)
+        removeErr := u.store.Delete(path)
         if removeErr != nil {
             return fmt.Errorf("failed to update database and remove file: %v, cleanup error: %w", err, removeErr)
         }
+        if errors.Is(err, repository.ErrEvidenceApproved) {
+            return fmt.Errorf("%w: approved evidence cannot be replaced", ErrPermissionDenied)
+        }
         return fmt.Errorf("failed to update database: %w", err)
     }
     return nil
 }
 
-func (u *eventInsideUsecase) GetFile(eventID uint, userID uint) (string, error) {
+// GetFile เปิดไฟล์หลักฐานจาก storage ผู้เรียกต้องปิด reader เอง
+func (u *eventInsideUsecase) GetFile(eventID uint, userID uint) (io.ReadCloser, error) {
     filePath, err := u.insideRepo.GetFilePath(eventID, userID)
     if err != nil {
-        return "", err
+        return nil, err
+    }
+    file, err := u.store.Open(filePath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open file: %w", err)
+    }
+    return file, nil
+}
+
+// FileKey ไฟล์หลักฐานปัจจุบันของ ownerID ใช้ผูกลิงก์ดาวน์โหลดไว้กับไฟล์นี้
+func (u *eventInsideUsecase) FileKey(eventID uint, ownerID uint) (string, error) {
+    filePath, err := u.insideRepo.GetFilePath(eventID, ownerID)
+    if err != nil {
+        return "", fmt.Errorf("%w: %v", ErrNotFound, err)
     }
     return filePath, nil
 }
 
-func (u *eventInsideUsecase) MyChecklist(userID uint,eventID uint) ([]entities.MyChecklist,error){
+// GetFileByKey เปิดไฟล์หลักฐานเมื่อยังเป็นไฟล์เดียวกับ fileKey ถ้าอัปโหลดไฟล์ใหม่แล้วถือว่าไม่พบ
+func (u *eventInsideUsecase) GetFileByKey(eventID uint, ownerID uint, fileKey string) (io.ReadCloser, error) {
+    filePath, err := u.FileKey(eventID, ownerID)
+    if err != nil {
+        return nil, err
+    }
+    if filePath != fileKey {
+        return nil, fmt.Errorf("%w: the file has been replaced since this link was created", ErrNotFound)
+    }
+    file, err := u.store.Open(filePath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open file: %w", err)
+    }
+    return file, nil
+}
+
+// GetFileFor ไฟล์หลักฐานของ ownerID ให้เฉพาะเจ้าของ, admin, ผู้รับรองของกิจกรรม และเจ้าหน้าที่คณะ
+func (u *eventInsideUsecase) GetFileFor(eventID uint, ownerID uint, requesterID uint, role string) (io.ReadCloser, error) {
+    if err := u.CanViewFile(eventID, ownerID, requesterID, role); err != nil {
+        return nil, err
+    }
+    return u.GetFile(eventID, ownerID)
+}
+
+func (u *eventInsideUsecase) CanViewFile(eventID uint, ownerID uint, requesterID uint, role string) error {
+    allowed, err := u.canViewFile(eventID, ownerID, requesterID, role)
+    if err != nil {
+        return err
+    }
+    if !allowed {
+        return fmt.Errorf("%w: you cannot access this file", ErrPermissionDenied)
+    }
+    return nil
+}
+
+func (u *eventInsideUsecase) canViewFile(eventID uint, ownerID uint, requesterID uint, role string) (bool, error) {
+    if requesterID == ownerID || role == "admin" || role == "superadmin" {
+        return true, nil
+    }
+    if role != "teacher" {
+        return false, nil
+    }
+    allowed, err := u.eventUsecase.HasEventPermission(eventID, requesterID, PermissionCertify)
+    if err != nil || allowed {
+        return allowed, err
+    }
+    isStaff, err := u.staffUsecase.IsStaffOfEvent(requesterID, eventID)
+    if err != nil || !isStaff {
+        return false, err
+    }
+    return u.staffUsecase.IsStaffOfStudent(requesterID, ownerID)
+}
+
+// GetFileVersions ประวัติไฟล์หลักฐาน ใช้สิทธิ์เดียวกับการดูไฟล์
+func (u *eventInsideUsecase) GetFileVersions(eventID uint, ownerID uint, requesterID uint, role string) ([]entities.EvidenceVersion, error) {
+    if err := u.CanViewFile(eventID, ownerID, requesterID, role); err != nil {
+        return nil, err
+    }
+    versions, err := u.evidenceRepo.GetVersions(eventID, ownerID)
+    if err != nil {
+        return nil, err
+    }
+    if versions == nil {
+        versions = []entities.EvidenceVersion{}
+    }
+    return versions, nil
+}
+
+func (u *eventInsideUsecase) GetFileVersion(versionID uint, requesterID uint, role string) (io.ReadCloser, error) {
+    version, err := u.evidenceRepo.GetVersion(versionID)
+    if err != nil {
+        return nil, err
+    }
+    if err := u.CanViewFile(version.EventID, version.UserID, requesterID, role); err != nil {
+        return nil, err
+    }
+    if version.PurgedAt != nil {
+        return nil, fmt.Errorf("version %d has been removed after the retention period", version.Version)
+    }
+    file, err := u.store.Open(version.FileKey)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open file: %w", err)
+    }
+    return file, nil
+}
+
+// MyChecklist admin ดูได้ทุกกิจกรรม นอกนั้นต้องเป็นผู้สร้างหรือผู้จัดร่วมที่มีสิทธิ์ดูรายชื่อ
+func (u *eventInsideUsecase) MyChecklist(userID uint, role string, eventID uint) ([]entities.MyChecklist,error){
+    if role != "admin" {
+        allowed, err := u.eventUsecase.HasEventPermission(eventID, userID, PermissionViewChecklist)
+        if err != nil {
+            return nil, err
+        }
+        if !allowed {
+            return nil, fmt.Errorf("%w: you cannot view the checklist of this event", ErrPermissionDenied)
+        }
+    }
     checklist,err:=u.insideRepo.MyChecklist(userID,eventID)
     if err != nil {
 		return nil, err
 	}
     var res []entities.MyChecklist
     for _, inside := range checklist {
-		mappedEvent := entities.MyChecklist{
-            EventID: inside.EventId,
-            UserID: inside.User,
-            TitleName: inside.Student.TitleName,
-            FirstName: inside.Student.FirstName,
-            LastName: inside.Student.LastName,
-            Code: inside.Student.Code,
-            Certifier: inside.Certifier,
-            Status: inside.Status,
-            Comment: inside.Comment,
-            FilePDF: inside.FilePDF,
-        }
-		res = append(res,mappedEvent)
+		res = append(res,mapChecklist(inside))
 	}
     return res,nil
 }
 
-func (u *eventInsideUsecase) UpdateEventStatusAndComment(eventID uint, userID uint, status bool, comment string) error{
-	return u.insideRepo.UpdateEventStatusAndComment(eventID,userID,status,comment)
+// AttendanceSheet ใบลงชื่อเข้าร่วมกิจกรรม ใช้สิทธิ์เดียวกับการดูรายชื่อ เรียงตามสาขาแล้วตามรหัสนักศึกษา
+func (u *eventInsideUsecase) AttendanceSheet(userID uint, role string, eventID uint) ([]byte, string, error) {
+    if role != "admin" {
+        allowed, err := u.eventUsecase.HasEventPermission(eventID, userID, PermissionViewChecklist)
+        if err != nil {
+            return nil, "", err
+        }
+        if !allowed {
+            return nil, "", fmt.Errorf("%w: you cannot view the checklist of this event", ErrPermissionDenied)
+        }
+    }
+    checklist, err := u.insideRepo.MyChecklist(userID, eventID)
+    if err != nil {
+        return nil, "", err
+    }
+    if len(checklist) == 0 {
+        return nil, "", fmt.Errorf("%w: event has no participants", ErrNotFound)
+    }
+    event, err := u.eventUsecase.GetEventByID(eventID)
+    if err != nil {
+        return nil, "", fmt.Errorf("%w: event not found", ErrNotFound)
+    }
+    sort.SliceStable(checklist, func(i, j int) bool {
+        a, b := checklist[i].Student, checklist[j].Student
+        if a.Branch.BranchName != b.Branch.BranchName {
+            return a.Branch.BranchName < b.Branch.BranchName
+        }
+        return a.Code < b.Code
+    })
+
+    data := filesystem.AttendanceData{
+        EventName:   checklist[0].Event.EventName,
+        Location:    checklist[0].Event.Location,
+        StartDate:   checklist[0].Event.StartDate,
+        WorkingHour: checklist[0].Event.WorkingHour,
+        Organizer:   event.Creator.TitleName + event.Creator.FirstName + " " + event.Creator.LastName,
+    }
+    for _, inside := range checklist {
+        data.Participants = append(data.Participants, filesystem.AttendanceRow{
+            Code:   inside.Student.Code,
+            Name:   inside.Student.TitleName + inside.Student.FirstName + " " + inside.Student.LastName,
+            Branch: inside.Student.Branch.BranchName,
+        })
+    }
+    pdf, err := u.renderer.AttendanceSheet(data)
+    if err != nil {
+        return nil, "", fmt.Errorf("error creating PDF: %w", err)
+    }
+    return pdf, fmt.Sprintf("attendance_event_%d.pdf", eventID), nil
+}
+
+// isRejected ผู้รับรองตรวจแล้วแต่ไม่อนุมัติ เมื่อนักศึกษาส่งหลักฐานใหม่ certified_at จะถูกล้างและกลับเป็นรอรับรอง
+func isRejected(status bool, certifiedAt *time.Time) bool {
+	return !status && certifiedAt != nil
+}
+
+func mapChecklist(inside entities.EventInside) entities.MyChecklist {
+    return entities.MyChecklist{
+        EventID: inside.EventId,
+        UserID: inside.User,
+        TitleName: inside.Student.TitleName,
+        FirstName: inside.Student.FirstName,
+        LastName: inside.Student.LastName,
+        Code: inside.Student.Code,
+        Certifier: inside.Certifier,
+        Status: inside.Status,
+        Comment: inside.Comment,
+        FilePDF: inside.FilePDF,
+        CertifiedBy: inside.CertifiedBy,
+        CertifiedAt: inside.CertifiedAt,
+    }
+}
+
+// UpdateEventStatusAndComment รับรองการเข้าร่วม โดยบันทึกผู้ที่รับรองจริงไว้ใน CertifiedBy
+func (u *eventInsideUsecase) UpdateEventStatusAndComment(eventID uint, userID uint, certifierID uint, role string, status bool, comment string) error{
+	if role != "admin" {
+		allowed, err := u.eventUsecase.HasEventPermission(eventID, certifierID, PermissionCertify)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("%w: you cannot certify participants of this event", ErrPermissionDenied)
+		}
+	}
+	joined, err := u.insideRepo.IsUserJoinedEvent(eventID, userID)
+	if err != nil {
+		return err
+	}
+	if !joined {
+		return fmt.Errorf("user is not a member of this event")
+	}
+	return u.insideRepo.UpdateEventStatusAndComment(eventID,userID,certifierID,status,comment)
+}
+
+// BulkCertify รับรองหรือตีกลับผู้เข้าร่วมหลายคนใน transaction เดียว พร้อมสร้างข่าวแจ้งนักศึกษาแต่ละคน
+// รายการที่ไม่ผ่านการตรวจ (ไม่ได้เข้าร่วม ซ้ำ หรืออยู่นอกคณะของเจ้าหน้าที่) จะถูกข้ามและรายงานเป็น failed
+// staff คือรับรองในฐานะเจ้าหน้าที่คณะ ซึ่งรับรองได้เฉพาะนักศึกษาในคณะของตน
+func (u *eventInsideUsecase) BulkCertify(eventID uint, certifierID uint, role string, staff bool, req *entities.BulkCertifyRequest) (*entities.BulkCertifyReport, error) {
+	if req.AllUploaded == (len(req.Items) > 0) {
+		return nil, fmt.Errorf("%w: specify either items or all_uploaded", ErrInvalidRequest)
+	}
+	event, err := u.eventUsecase.GetEventByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	var branchIDs []uint
+	if staff && role != "admin" {
+		if branchIDs, err = u.staffUsecase.BranchIDs(certifierID); err != nil {
+			return nil, err
+		}
+		if len(branchIDs) == 0 {
+			return nil, fmt.Errorf("%w: you are not staff of any faculty", ErrPermissionDenied)
+		}
+		owned, err := u.staffUsecase.OwnsEvent(certifierID, event)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return nil, fmt.Errorf("%w: event is not in your faculty", ErrPermissionDenied)
+		}
+	} else if !staff && role != "admin" {
+		allowed, err := u.eventUsecase.HasEventPermission(eventID, certifierID, PermissionCertify)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: you cannot certify participants of this event", ErrPermissionDenied)
+		}
+	}
+
+	participants, err := u.insideRepo.MyChecklist(certifierID, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	joined := make(map[uint]*entities.EventInside, len(participants))
+	for i := range participants {
+		joined[participants[i].User] = &participants[i]
+	}
+	inScope := func(participant *entities.EventInside) bool {
+		return branchIDs == nil || utility.ContainsUint(branchIDs, participant.Student.BranchId)
+	}
+
+	report := &entities.BulkCertifyReport{EventID: eventID}
+	if req.AllUploaded {
+		for i := range participants {
+			p := &participants[i]
+			if p.Status || p.FilePDF == "" || isRejected(p.Status, p.CertifiedAt) || !inScope(p) {
+				continue
+			}
+			report.Rows = append(report.Rows, entities.BulkCertifyResult{
+				UserID:  participants[i].User,
+				Status:  req.Status,
+				Comment: req.Comment,
+			})
+		}
+	} else {
+		seen := map[uint]bool{}
+		for _, item := range req.Items {
+			row := entities.BulkCertifyResult{UserID: item.UserID, Status: req.Status, Comment: req.Comment}
+			if item.Status != nil {
+				row.Status = *item.Status
+			}
+			if item.Comment != nil {
+				row.Comment = *item.Comment
+			}
+			participant := joined[item.UserID]
+			switch {
+			case seen[item.UserID]:
+				row.Result, row.Error = entities.CertifyFailed, "duplicate user in request"
+			case participant == nil:
+				row.Result, row.Error = entities.CertifyFailed, "user is not a member of this event"
+			case !inScope(participant):
+				row.Result, row.Error = entities.CertifyFailed, "student is not in your faculty"
+			}
+			seen[item.UserID] = true
+			report.Rows = append(report.Rows, row)
+		}
+	}
+
+	var certified []entities.BulkCertifyResult
+	var news []entities.News
+	for _, row := range report.Rows {
+		if row.Result == entities.CertifyFailed {
+			continue
+		}
+		certified = append(certified, row)
+		news = append(news, certifyNews(event.EventName, row))
+	}
+	if len(certified) > 0 {
+		if err := u.insideRepo.CertifyMany(eventID, certifierID, certified, news, u.txManager); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		switch {
+		case row.Result == entities.CertifyFailed:
+			report.Failed++
+		case row.Status:
+			row.Result = entities.CertifyApproved
+			report.Approved++
+		default:
+			row.Result = entities.CertifyRejected
+			report.Rejected++
+		}
+	}
+	report.Total = uint(len(report.Rows))
+	return report, nil
+}
+
+// certifyNews ข่าวแจ้งผลการรับรองถึงนักศึกษา
+func certifyNews(eventName string, row entities.BulkCertifyResult) entities.News {
+	message := fmt.Sprintf("กิจกรรม'%s' ได้รับการรับรองแล้ว", eventName)
+	if !row.Status {
+		message = fmt.Sprintf("กิจกรรม'%s' ไม่ผ่านการรับรอง", eventName)
+	}
+	if row.Comment != "" {
+		message += fmt.Sprintf(" ความคิดเห็น: %s", row.Comment)
+	}
+	return entities.News{
+		Title:   "ผลการรับรองกิจกรรม",
+		Userid:  row.UserID,
+		Message: message,
+	}
 }
 
 func (u *eventInsideUsecase) CountEventInside(eventID uint) (uint,error){
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/infrastructure/storage"
+	"fmt"
+	"time"
+)
+
+// MaintenanceUsecase งานดูแลระบบที่รันเป็นรอบ ไม่ได้เรียกผ่าน API
+type MaintenanceUsecase interface {
+	PurgeSupersededEvidence(retention time.Duration) (int, error)
+	ReconcileStorage(dryRun bool, grace time.Duration) (*entities.StorageReport, error)
+}
+
+type maintenanceUsecase struct {
+	evidenceRepo repository.EvidenceRepository
+	fileRefRepo  repository.FileReferenceRepository
+	store        storage.Storage
+}
+
+func NewMaintenanceUsecase(evidenceRepo repository.EvidenceRepository, fileRefRepo repository.FileReferenceRepository, store storage.Storage) MaintenanceUsecase {
+	return &maintenanceUsecase{
+		evidenceRepo: evidenceRepo,
+		fileRefRepo:  fileRefRepo,
+		store:        store,
+	}
+}
+
+// PurgeSupersededEvidence ลบไฟล์หลักฐานเวอร์ชันเก่าที่ถูกแทนที่นานเกิน retention คืนจำนวนไฟล์ที่ลบ
+func (u *maintenanceUsecase) PurgeSupersededEvidence(retention time.Duration) (int, error) {
+	versions, err := u.evidenceRepo.GetPurgeable(time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get superseded versions: %w", err)
+	}
+	purged := 0
+	for _, version := range versions {
+		if err := u.store.Delete(version.FileKey); err != nil {
+			return purged, fmt.Errorf("failed to remove file %s: %w", version.FileKey, err)
+		}
+		if err := u.evidenceRepo.MarkPurged(version.VersionID); err != nil {
+			return purged, fmt.Errorf("failed to mark version %d as purged: %w", version.VersionID, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// ReconcileStorage เทียบไฟล์ใน storage กับการอ้างอิงในฐานข้อมูล
+// ไฟล์ที่ไม่มีใครอ้างถึงและเก่ากว่า grace จะถูกลบ (ไฟล์ใหม่อาจกำลังอยู่ระหว่างอัปโหลด)
+// แถวที่อ้างถึงไฟล์ที่ไม่มีอยู่จะถูกล้างการอ้างอิง ถ้า dryRun จะรายงานอย่างเดียว
+func (u *maintenanceUsecase) ReconcileStorage(dryRun bool, grace time.Duration) (*entities.StorageReport, error) {
+	refs, err := u.fileRefRepo.GetFileReferences()
+	if err != nil {
+		return nil, err
+	}
+	objects, err := u.store.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage: %w", err)
+	}
+
+	report := &entities.StorageReport{
+		DryRun:       dryRun,
+		ScannedFiles: len(objects),
+		References:   len(refs),
+		Orphans:      []string{},
+		Missing:      []entities.FileReference{},
+	}
+
+	referenced := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		referenced[storage.NormalizeKey(ref.Key)] = true
+	}
+	existing := make(map[string]bool, len(objects))
+	cutoff := time.Now().Add(-grace)
+	for _, object := range objects {
+		existing[object.Key] = true
+		if referenced[object.Key] || object.ModTime.After(cutoff) {
+			continue
+		}
+		report.Orphans = append(report.Orphans, object.Key)
+		if dryRun {
+			continue
+		}
+		if err := u.store.Delete(object.Key); err != nil {
+			return report, fmt.Errorf("failed to remove orphaned file %s: %w", object.Key, err)
+		}
+		report.Removed++
+	}
+
+	for _, ref := range refs {
+		if existing[storage.NormalizeKey(ref.Key)] {
+			continue
+		}
+		report.Missing = append(report.Missing, ref)
+		if dryRun {
+			continue
+		}
+		if err := u.fileRefRepo.ClearFileReference(ref); err != nil {
+			return report, fmt.Errorf("failed to repair %s reference to %s: %w", ref.Source, ref.Key, err)
+		}
+		report.Repaired++
+	}
+	return report, nil
+}
@@ -3,8 +3,10 @@ package usecase
 import (
 	"RESTAPI/domain/entities"
 	"RESTAPI/domain/repository"
+	"RESTAPI/infrastructure/storage"
 	filesystem "RESTAPI/utility/fileSystem"
 	"fmt"
+	"strings"
 
 	// "RESTAPI/usecase"
 	"RESTAPI/utility"
@@ -13,26 +15,81 @@ import (
 
 type OutsideUsecase interface {
 	CreateOutside(req entities.OutsideRequest, userID uint) (uint, error) 
-	GetOutsideByID(id uint) (*entities.OutsideResponse, error)
-	CreateFile(id uint) ([]byte, string, error)
+	GetOutsideByID(id uint, userID uint, role string) (*entities.OutsideResponse, error)
+	CreateFile(id uint, userID uint, role string) ([]byte, string, error)
+	CreateYearFile(ownerID uint, year uint, userID uint, role string) ([]byte, string, error)
+	MyOutside(userID uint) ([]entities.MyOutside, error)
+	EditOutside(id uint, req entities.OutsideRequest, userID uint) error
+	DeleteOutside(id uint, userID uint) error
+	CanAccess(id uint, userID uint, role string) (*entities.EventOutside, error)
 }
 
 type outsideUsecase struct {
 	repo repository.OutsideRepository
+	attachmentRepo repository.AttachmentRepository
+	staffUsecase StaffUsecase
+	store storage.Storage
+	renderer *filesystem.PDFRenderer
+	documentUsecase DocumentUsecase
+	categoryRepo repository.CategoryRepository
 }
 
-func NewOutsideUsecase(repo repository.OutsideRepository) OutsideUsecase {
+func NewOutsideUsecase(repo repository.OutsideRepository, attachmentRepo repository.AttachmentRepository, staffUsecase StaffUsecase, store storage.Storage, renderer *filesystem.PDFRenderer, documentUsecase DocumentUsecase, categoryRepo repository.CategoryRepository) OutsideUsecase {
 	return &outsideUsecase{
 		repo: repo,
+		attachmentRepo: attachmentRepo,
+		staffUsecase: staffUsecase,
+		store: store,
+		renderer: renderer,
+		documentUsecase: documentUsecase,
+		categoryRepo: categoryRepo,
 	}
 }
 
+// canAccess เจ้าของ, admin และเจ้าหน้าที่คณะของนักศึกษาเจ้าของข้อมูล เข้าถึงได้
+func (u *outsideUsecase) canAccess(outside *entities.EventOutside, userID uint, role string) (bool, error) {
+	return u.canAccessOwner(outside.User, userID, role)
+}
+
+// canAccessOwner ผู้ใช้เข้าถึงกิจกรรมภายนอกของนักศึกษา ownerID ได้หรือไม่ ตรวจจากข้อมูลนักศึกษาโดยไม่ต้องโหลดกิจกรรม
+func (u *outsideUsecase) canAccessOwner(ownerID uint, userID uint, role string) (bool, error) {
+	if ownerID == userID {
+		return true, nil
+	}
+	switch role {
+	case "admin", "superadmin":
+		return true, nil
+	case "teacher":
+		return u.staffUsecase.IsStaffOfStudent(userID, ownerID)
+	}
+	return false, nil
+}
+
+// CanAccess คืนข้อมูลกิจกรรมภายนอกถ้าผู้ใช้มีสิทธิ์เข้าถึง
+func (u *outsideUsecase) CanAccess(id uint, userID uint, role string) (*entities.EventOutside, error) {
+	outside, err := u.repo.GetOutsideByID(id)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := u.canAccess(outside, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: you cannot access this record", ErrPermissionDenied)
+	}
+	return outside, nil
+}
+
 func (u *outsideUsecase) CreateOutside(req entities.OutsideRequest, userID uint) (uint, error) {
 
 	startDate, err := utility.ParseStartDate(req.StartDate)
 	if err != nil {
 		return 0, err
 	}
+	if _, err := findCategory(u.categoryRepo, req.CategoryID); err != nil {
+		return 0, err
+	}
 	outside := &entities.EventOutside{
 		User:        userID,
 		EventName:   req.EventName,
@@ -41,6 +98,7 @@ func (u *outsideUsecase) CreateOutside(req entities.OutsideRequest, userID uint)
 		Intendant:   req.Intendant,
 		Location:    req.Location,
 		WorkingHour: req.WorkingHour,
+		CategoryID: req.CategoryID,
 	}
 	id, err := u.repo.CreateOutside(outside)
 	if err != nil {
@@ -49,12 +107,17 @@ func (u *outsideUsecase) CreateOutside(req entities.OutsideRequest, userID uint)
 
 	return id, nil
 }
-func (u *outsideUsecase) GetOutsideByID(id uint) (*entities.OutsideResponse, error) {
-	outside, err := u.repo.GetOutsideByID(id)
+func (u *outsideUsecase) GetOutsideByID(id uint, userID uint, role string) (*entities.OutsideResponse, error) {
+	outside, err := u.CanAccess(id, userID, role)
 	if err != nil {
 		return nil, err
 	}
-	outsideRes := entities.OutsideResponse{
+	outsideRes := mapOutsideResponse(*outside)
+	return &outsideRes, nil
+}
+
+func mapOutsideResponse(outside entities.EventOutside) entities.OutsideResponse {
+	return entities.OutsideResponse{
 		EventID:     outside.EventID,
 		EventName:   outside.EventName,
 		Location:    outside.Location,
@@ -62,6 +125,8 @@ func (u *outsideUsecase) GetOutsideByID(id uint) (*entities.OutsideResponse, err
 		StartDate:   outside.StartDate,
 		WorkingHour: outside.WorkingHour,
 		Intendant:   outside.Intendant,
+		CategoryID:   outside.CategoryID,
+		CategoryName: categoryName(outside.Category),
 		Student: entities.StudentResponse{
 			UserID:      outside.Student.UserID,
 			TitleName:   outside.Student.TitleName,
@@ -75,16 +140,14 @@ func (u *outsideUsecase) GetOutsideByID(id uint) (*entities.OutsideResponse, err
 			FacultyName: outside.Student.Branch.Faculty.FacultyName,
 		},
 	}
-	return &outsideRes, nil
-
 }
 
-func (u *outsideUsecase) CreateFile(id uint) ([]byte, string, error){
-	data, err := u.GetOutsideByID(id)
+func (u *outsideUsecase) CreateFile(id uint, userID uint, role string) ([]byte, string, error){
+	data, err := u.GetOutsideByID(id, userID, role)
 	if err != nil {
-		return nil, "", fmt.Errorf("data not found: %v", err)
+		return nil, "", fmt.Errorf("data not found: %w", err)
 	}
-	pdfBytes, fileName, err := filesystem.CreatePDF(*data)
+	pdfBytes, fileName, err := u.issueForm([]entities.OutsideResponse{*data}, userID)
 	if err != nil {
 		return nil, " " , fmt.Errorf("error creating PDF: %v", err)
 	}
@@ -93,3 +156,133 @@ func (u *outsideUsecase) CreateFile(id uint) ([]byte, string, error){
 	return pdfBytes, fileName, nil
 	
 }
+
+
+// CreateYearFile แบบบันทึกที่รวมกิจกรรมภายนอกทั้งปีการศึกษาของ ownerID ไว้ในฟอร์มเดียว
+func (u *outsideUsecase) CreateYearFile(ownerID uint, year uint, userID uint, role string) ([]byte, string, error) {
+	allowed, err := u.canAccessOwner(ownerID, userID, role)
+	if err != nil {
+		return nil, "", err
+	}
+	if !allowed {
+		return nil, "", fmt.Errorf("%w: you cannot access these records", ErrPermissionDenied)
+	}
+	outsides, err := u.repo.GetOutsidesForForm(ownerID, year)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(outsides) == 0 {
+		return nil, "", fmt.Errorf("%w: no outside activities in school year %d", ErrNotFound, year)
+	}
+	records := make([]entities.OutsideResponse, 0, len(outsides))
+	for _, outside := range outsides {
+		records = append(records, mapOutsideResponse(outside))
+	}
+	pdfBytes, fileName, err := u.issueForm(records, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating PDF: %v", err)
+	}
+	return pdfBytes, fileName, nil
+}
+
+// issueForm สร้างแบบบันทึกพร้อม QR code และลงทะเบียนไว้ให้ตรวจสอบได้
+func (u *outsideUsecase) issueForm(records []entities.OutsideResponse, issuerID uint) ([]byte, string, error) {
+	var hours uint
+	activities := make([]string, 0, len(records))
+	for _, record := range records {
+		hours += record.WorkingHour
+		activities = append(activities, fmt.Sprintf("%s (%s, %d ชั่วโมง)", record.EventName, utility.FormatToThaiShortDate(record.StartDate), record.WorkingHour))
+	}
+	document := &entities.IssuedDocument{
+		Type:       entities.DocumentOutsideForm,
+		OwnerID:    records[0].Student.UserID,
+		SchoolYear: records[0].SchoolYear,
+		Title:      fmt.Sprintf("แบบบันทึกการเข้าร่วมกิจกรรม/โครงการจิตอาสา ประจำปีการศึกษา %d", records[0].SchoolYear),
+		Details:    fmt.Sprintf("%d กิจกรรม รวม %d ชั่วโมง: %s", len(records), hours, strings.Join(activities, ", ")),
+		IssuedBy:   issuerID,
+	}
+	if len(records) == 1 {
+		document.EventID = records[0].EventID
+	}
+	var fileName string
+	data, err := u.documentUsecase.Issue(document, filesystem.TemplateOutsideForm, func(verification *filesystem.Verification) ([]byte, error) {
+		pdf, name, err := u.renderer.OutsideForm(records, verification)
+		fileName = name
+		return pdf, err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return data, fileName, nil
+}
+
+func (u *outsideUsecase) MyOutside(userID uint) ([]entities.MyOutside, error) {
+	outside, err := u.repo.GetOutsidesByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	res := []entities.MyOutside{}
+	for _, event := range outside {
+		res = append(res, mapMyOutside(event))
+	}
+	return res, nil
+}
+
+// EditOutside แก้ไขได้เฉพาะเจ้าของข้อมูล
+func (u *outsideUsecase) EditOutside(id uint, req entities.OutsideRequest, userID uint) error {
+	outside, err := u.repo.GetOutsideByID(id)
+	if err != nil {
+		return err
+	}
+	if outside.User != userID {
+		return fmt.Errorf("%w: you cannot edit this record", ErrPermissionDenied)
+	}
+	startDate, err := utility.ParseStartDate(req.StartDate)
+	if err != nil {
+		return err
+	}
+	if _, err := findCategory(u.categoryRepo, req.CategoryID); err != nil {
+		return err
+	}
+	outside.EventName = req.EventName
+	outside.StartDate = startDate
+	outside.SchoolYear = req.SchoolYear
+	outside.Intendant = req.Intendant
+	outside.Location = req.Location
+	outside.WorkingHour = req.WorkingHour
+	outside.CategoryID = req.CategoryID
+	return u.repo.UpdateOutside(outside)
+}
+
+func (u *outsideUsecase) DeleteOutside(id uint, userID uint) error {
+	outside, err := u.repo.GetOutsideByID(id)
+	if err != nil {
+		return err
+	}
+	if outside.User != userID {
+		return fmt.Errorf("%w: you cannot delete this record", ErrPermissionDenied)
+	}
+	if err := u.repo.DeleteOutside(id, userID); err != nil {
+		return err
+	}
+	keys := []string{}
+	if outside.FilePDF != "" {
+		keys = append(keys, outside.FilePDF)
+	}
+	attachments, err := u.attachmentRepo.DeleteAttachments(entities.AttachmentOutside, id, userID)
+	if err != nil {
+		return fmt.Errorf("event deleted but failed to remove attachments: %w", err)
+	}
+	for _, attachment := range attachments {
+		keys = append(keys, attachment.FileKey)
+		if attachment.ThumbnailKey != "" {
+			keys = append(keys, attachment.ThumbnailKey)
+		}
+	}
+	for _, key := range keys {
+		if err := u.store.Delete(key); err != nil {
+			return fmt.Errorf("event deleted but failed to remove file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/domain/transaction"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type RequirementRuleRequest struct {
+	CategoryID uint `json:"category_id"`
+	MinHours   uint `json:"min_hours"`
+}
+
+// RequirementSetRequest ถ้าระบุสาขา คณะจะถูกกำหนดตามสาขานั้น
+type RequirementSetRequest struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	FacultyID   *uint                    `json:"faculty_id"`
+	BranchID    *uint                    `json:"branch_id"`
+	ClassYear   *uint                    `json:"class_year"`
+	PerYear     bool                     `json:"per_year"`
+	TotalHours  uint                     `json:"total_hours"`
+	Rules       []RequirementRuleRequest `json:"rules"`
+}
+
+// RequirementUsecase เกณฑ์ชั่วโมงกิจกรรมรายคณะ สาขา ชั้นปี และการประเมินนักศึกษาตามเกณฑ์
+type RequirementUsecase interface {
+	CreateRequirementSet(req *RequirementSetRequest) (*entities.RequirementSet, error)
+	GetAllRequirementSets() ([]entities.RequirementSet, error)
+	GetRequirementSet(id uint) (*entities.RequirementSet, error)
+	UpdateRequirementSet(id uint, req *RequirementSetRequest) (*entities.RequirementSet, error)
+	DeleteRequirementSet(id uint) error
+	Evaluate(studentID uint, schoolYear uint, requesterID uint, role string) (*entities.RequirementEvaluation, error)
+	EvaluateStudent(student *entities.Student, schoolYear uint) (*entities.RequirementEvaluation, error)
+	Evaluator(schoolYear uint) (RequirementEvaluator, error)
+}
+
+// RequirementEvaluator ประเมินนักศึกษาตามเกณฑ์ที่ตรงกับคณะ สาขา และชั้นปี ด้วยข้อมูลที่โหลดไว้ครั้งเดียว
+// ใช้กับรายงานและไฟล์ส่งออกที่ประเมินนักศึกษาหลายคน ผลตรงกับ Evaluate ของนักศึกษาคนเดียวกัน
+type RequirementEvaluator func(userID, facultyID, branchID, classYear uint) *entities.RequirementEvaluation
+
+type requirementUsecase struct {
+	requirementRepo repository.RequirementRepository
+	categoryRepo    repository.CategoryRepository
+	facultyRepo     repository.FacultyRepository
+	branchRepo      repository.BranchRepository
+	userRepo        repository.UserRepository
+	staffUsecase    StaffUsecase
+	txManager       transaction.TransactionManager
+	requiredHours   uint
+}
+
+// NewRequirementUsecase requiredHours คือชั่วโมงขั้นต่ำต่อปีการศึกษาที่ใช้เมื่อนักศึกษาไม่ตรงกับเกณฑ์ใด
+func NewRequirementUsecase(requirementRepo repository.RequirementRepository, categoryRepo repository.CategoryRepository, facultyRepo repository.FacultyRepository, branchRepo repository.BranchRepository, userRepo repository.UserRepository, staffUsecase StaffUsecase, txManager transaction.TransactionManager, requiredHours uint) RequirementUsecase {
+	return &requirementUsecase{
+		requirementRepo: requirementRepo,
+		categoryRepo:    categoryRepo,
+		facultyRepo:     facultyRepo,
+		branchRepo:      branchRepo,
+		userRepo:        userRepo,
+		staffUsecase:    staffUsecase,
+		txManager:       txManager,
+		requiredHours:   requiredHours,
+	}
+}
+
+func (u *requirementUsecase) apply(set *entities.RequirementSet, req *RequirementSetRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRequest)
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return fmt.Errorf("%w: name must be at most 100 characters", ErrInvalidRequest)
+	}
+	if req.ClassYear != nil && *req.ClassYear == 0 {
+		return fmt.Errorf("%w: class_year must be greater than 0", ErrInvalidRequest)
+	}
+	facultyID := req.FacultyID
+	if req.BranchID != nil {
+		exists, err := u.branchRepo.BranchExists(*req.BranchID)
+		if err != nil {
+			return fmt.Errorf("failed to check branch: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: branch %d does not exist", ErrInvalidRequest, *req.BranchID)
+		}
+		branch, err := u.branchRepo.GetBranch(*req.BranchID)
+		if err != nil {
+			return fmt.Errorf("failed to get branch: %w", err)
+		}
+		if facultyID != nil && *facultyID != branch.FacultyId {
+			return fmt.Errorf("%w: branch %d is not in faculty %d", ErrInvalidRequest, branch.BranchID, *facultyID)
+		}
+		facultyID = &branch.FacultyId
+	} else if facultyID != nil {
+		if _, err := u.facultyRepo.GetFacultyByID(*facultyID); err != nil {
+			return fmt.Errorf("%w: faculty %d does not exist", ErrInvalidRequest, *facultyID)
+		}
+	}
+
+	var rules []entities.RequirementRule
+	var sum uint
+	seen := map[uint]bool{}
+	for _, rule := range req.Rules {
+		if rule.MinHours == 0 {
+			return fmt.Errorf("%w: min_hours must be greater than 0", ErrInvalidRequest)
+		}
+		if seen[rule.CategoryID] {
+			return fmt.Errorf("%w: category %d is listed more than once", ErrInvalidRequest, rule.CategoryID)
+		}
+		seen[rule.CategoryID] = true
+		categoryID := rule.CategoryID
+		if _, err := findCategory(u.categoryRepo, &categoryID); err != nil {
+			return err
+		}
+		rules = append(rules, entities.RequirementRule{CategoryID: rule.CategoryID, MinHours: rule.MinHours})
+		sum += rule.MinHours
+	}
+	if req.TotalHours == 0 && len(rules) == 0 {
+		return fmt.Errorf("%w: total_hours or at least one rule is required", ErrInvalidRequest)
+	}
+	// ชั่วโมงรายหมวดหมู่นับรวมในชั่วโมงรวม ชั่วโมงรวมจึงต้องไม่น้อยกว่าผลรวมของหมวดหมู่
+	if req.TotalHours > 0 && req.TotalHours < sum {
+		return fmt.Errorf("%w: total_hours must be at least the sum of category minimums (%d)", ErrInvalidRequest, sum)
+	}
+
+	existing, err := u.requirementRepo.GetRequirementSetByScope(facultyID, req.BranchID, req.ClassYear)
+	if err != nil {
+		return fmt.Errorf("failed to check requirement scope: %w", err)
+	}
+	if existing != nil && existing.RequirementSetID != set.RequirementSetID {
+		return fmt.Errorf("%w: requirement set %q already covers this faculty, branch and class year", ErrInvalidRequest, existing.Name)
+	}
+
+	set.Name = name
+	set.Description = strings.TrimSpace(req.Description)
+	set.FacultyID = facultyID
+	set.BranchID = req.BranchID
+	set.ClassYear = req.ClassYear
+	set.PerYear = req.PerYear
+	set.TotalHours = req.TotalHours
+	set.Rules = rules
+	return nil
+}
+
+func (u *requirementUsecase) CreateRequirementSet(req *RequirementSetRequest) (*entities.RequirementSet, error) {
+	set := &entities.RequirementSet{}
+	if err := u.apply(set, req); err != nil {
+		return nil, err
+	}
+	if err := u.requirementRepo.CreateRequirementSet(set); err != nil {
+		return nil, fmt.Errorf("failed to create requirement set: %w", err)
+	}
+	return u.GetRequirementSet(set.RequirementSetID)
+}
+
+func (u *requirementUsecase) GetAllRequirementSets() ([]entities.RequirementSet, error) {
+	sets, err := u.requirementRepo.GetAllRequirementSets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirement sets: %w", err)
+	}
+	if sets == nil {
+		sets = []entities.RequirementSet{}
+	}
+	return sets, nil
+}
+
+func (u *requirementUsecase) GetRequirementSet(id uint) (*entities.RequirementSet, error) {
+	set, err := u.requirementRepo.GetRequirementSet(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirement set: %w", err)
+	}
+	if set == nil {
+		return nil, fmt.Errorf("%w: requirement set %d", ErrNotFound, id)
+	}
+	return set, nil
+}
+
+func (u *requirementUsecase) UpdateRequirementSet(id uint, req *RequirementSetRequest) (*entities.RequirementSet, error) {
+	set, err := u.GetRequirementSet(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.apply(set, req); err != nil {
+		return nil, err
+	}
+	if err := u.requirementRepo.UpdateRequirementSet(set, u.txManager); err != nil {
+		return nil, err
+	}
+	return u.GetRequirementSet(id)
+}
+
+func (u *requirementUsecase) DeleteRequirementSet(id uint) error {
+	if _, err := u.GetRequirementSet(id); err != nil {
+		return err
+	}
+	return u.requirementRepo.DeleteRequirementSet(id)
+}
+
+// matchRequirementSet เลือกเกณฑ์ที่เจาะจงที่สุด ถ้าเท่ากันใช้เกณฑ์ที่สร้างก่อน
+func matchRequirementSet(sets []entities.RequirementSet) *entities.RequirementSet {
+	var best *entities.RequirementSet
+	bestScore := -1
+	for i := range sets {
+		score := 0
+		if sets[i].BranchID != nil {
+			score += 4
+		}
+		if sets[i].FacultyID != nil {
+			score += 2
+		}
+		if sets[i].ClassYear != nil {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = &sets[i], score
+		}
+	}
+	return best
+}
+
+// evaluateRule ตรวจชั่วโมงของข้อกำหนดหนึ่งข้อ label คือชื่อที่ใช้ในคำอธิบาย
+func evaluateRule(result entities.RuleResult, label string) entities.RuleResult {
+	result.Met = result.EarnedHours >= result.RequiredHours
+	result.Explanation = fmt.Sprintf("%s %d จาก %d ชั่วโมง", label, result.EarnedHours, result.RequiredHours)
+	if result.Met {
+		result.Explanation += " ครบตามเกณฑ์"
+		return result
+	}
+	result.MissingHours = result.RequiredHours - result.EarnedHours
+	result.Explanation += fmt.Sprintf(" ขาดอีก %d ชั่วโมง", result.MissingHours)
+	if result.PendingHours > 0 {
+		result.Explanation += fmt.Sprintf(" (รอรับรอง %d ชั่วโมง)", result.PendingHours)
+	}
+	return result
+}
+
+// matchScope เกณฑ์ที่ใช้กับนักศึกษาของคณะ สาขา และชั้นปีที่กำหนด เงื่อนไขเดียวกับ MatchingRequirementSets
+func matchScope(sets []entities.RequirementSet, facultyID, branchID, classYear uint) *entities.RequirementSet {
+	matching := []entities.RequirementSet{}
+	for _, set := range sets {
+		if (set.FacultyID == nil || *set.FacultyID == facultyID) &&
+			(set.BranchID == nil || *set.BranchID == branchID) &&
+			(set.ClassYear == nil || *set.ClassYear == classYear) {
+			matching = append(matching, set)
+		}
+	}
+	return matchRequirementSet(matching)
+}
+
+// evaluationYear เกณฑ์ที่กำหนดเป็นชั่วโมงตลอดหลักสูตรจะนับกิจกรรมทุกปีการศึกษาเสมอ
+func evaluationYear(set *entities.RequirementSet, schoolYear uint) uint {
+	if set != nil && !set.PerYear {
+		return 0
+	}
+	return schoolYear
+}
+
+// evaluateHours ประเมินชั่วโมงแยกหมวดหมู่ตามเกณฑ์ set (nil คือใช้ requiredHours ต่อปี)
+// hours ต้องเป็นชั่วโมงของปีการศึกษา evaluationYear(set, schoolYear) ถ้าตลอดหลักสูตรชั่วโมงต่อปีจะคูณด้วยชั้นปี
+func (u *requirementUsecase) evaluateHours(set *entities.RequirementSet, classYear uint, schoolYear uint, hours []entities.CategoryHours) *entities.RequirementEvaluation {
+	evaluation := &entities.RequirementEvaluation{Rules: []entities.RuleResult{}}
+	perYear, totalHours := true, u.requiredHours
+	if set != nil {
+		evaluation.RequirementSetID = &set.RequirementSetID
+		evaluation.RequirementSetName = set.Name
+		perYear, totalHours = set.PerYear, set.TotalHours
+	}
+	schoolYear = evaluationYear(set, schoolYear)
+	multiplier := uint(1)
+	if perYear && schoolYear == 0 && classYear > 1 {
+		multiplier = classYear
+	}
+	evaluation.SchoolYear = schoolYear
+
+	byCategory := map[uint]entities.CategoryHours{}
+	categories := make([]entities.CategoryHours, 0, len(hours))
+	for _, h := range hours {
+		if h.CategoryID == 0 {
+			h.CategoryName = uncategorized
+		}
+		byCategory[h.CategoryID] = h
+		categories = append(categories, h)
+		evaluation.EarnedHours += h.EarnedHours
+		evaluation.PendingHours += h.PendingHours
+	}
+	evaluation.Categories = categories
+
+	if totalHours > 0 {
+		evaluation.Rules = append(evaluation.Rules, evaluateRule(entities.RuleResult{
+			Kind:          entities.RequirementTotal,
+			RequiredHours: totalHours * multiplier,
+			EarnedHours:   evaluation.EarnedHours,
+			PendingHours:  evaluation.PendingHours,
+		}, "ชั่วโมงรวม"))
+	}
+	if set != nil {
+		for _, rule := range set.Rules {
+			name := ""
+			if rule.Category != nil {
+				name = rule.Category.Name
+			}
+			earned := byCategory[rule.CategoryID]
+			evaluation.Rules = append(evaluation.Rules, evaluateRule(entities.RuleResult{
+				Kind:          entities.RequirementCategory,
+				CategoryID:    rule.CategoryID,
+				CategoryName:  name,
+				RequiredHours: rule.MinHours * multiplier,
+				EarnedHours:   earned.EarnedHours,
+				PendingHours:  earned.PendingHours,
+			}, "หมวด "+name))
+		}
+	}
+
+	missing := 0
+	for _, rule := range evaluation.Rules {
+		if !rule.Met {
+			missing++
+		}
+	}
+	evaluation.Met = missing == 0
+	switch {
+	case len(evaluation.Rules) == 0:
+		evaluation.Summary = "ไม่มีเกณฑ์ชั่วโมงที่กำหนด"
+	case evaluation.Met:
+		evaluation.Summary = "ผ่านเกณฑ์ครบทุกข้อ"
+	default:
+		evaluation.Summary = fmt.Sprintf("ยังไม่ผ่านเกณฑ์ %d จาก %d ข้อ", missing, len(evaluation.Rules))
+	}
+	return evaluation
+}
+
+// Evaluate ประเมินชั่วโมงของนักศึกษาตามเกณฑ์ที่ตรงกับคณะ สาขา และชั้นปี ดูได้เฉพาะเจ้าของ admin และเจ้าหน้าที่คณะ
+// schoolYear เป็น 0 คือตลอดหลักสูตร ชั่วโมงต่อปีจะคูณด้วยชั้นปีของนักศึกษา
+func (u *requirementUsecase) Evaluate(studentID uint, schoolYear uint, requesterID uint, role string) (*entities.RequirementEvaluation, error) {
+	if requesterID != studentID && role != "admin" {
+		allowed, err := u.staffUsecase.IsStaffOfStudent(requesterID, studentID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: student is not in your faculty", ErrPermissionDenied)
+		}
+	}
+	student, err := u.userRepo.GetStudentByUserID(studentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: student %d", ErrNotFound, studentID)
+	}
+	return u.EvaluateStudent(student, schoolYear)
+}
+
+// EvaluateStudent ประเมินนักศึกษาโดยไม่ตรวจสิทธิ์ ผู้เรียกต้องตรวจสิทธิ์เอง
+func (u *requirementUsecase) EvaluateStudent(student *entities.Student, schoolYear uint) (*entities.RequirementEvaluation, error) {
+	sets, err := u.requirementRepo.MatchingRequirementSets(student.Branch.FacultyId, student.BranchId, student.Year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirement sets: %w", err)
+	}
+	set := matchRequirementSet(sets)
+	hours, err := u.requirementRepo.HoursByCategory(student.UserID, evaluationYear(set, schoolYear))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hours: %w", err)
+	}
+	evaluation := u.evaluateHours(set, student.Year, schoolYear, hours)
+	evaluation.UserID = student.UserID
+	evaluation.Code = student.Code
+	evaluation.Name = student.TitleName + student.FirstName + " " + student.LastName
+	return evaluation, nil
+}
+
+// Evaluator โหลดเกณฑ์ทั้งหมดและชั่วโมงแยกหมวดหมู่ของนักศึกษาทุกคนครั้งเดียว ทั้งของปีการศึกษา schoolYear และตลอดหลักสูตร
+func (u *requirementUsecase) Evaluator(schoolYear uint) (RequirementEvaluator, error) {
+	sets, err := u.requirementRepo.GetAllRequirementSets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirement sets: %w", err)
+	}
+	rows, err := u.requirementRepo.StudentCategoryHours(schoolYear)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hours: %w", err)
+	}
+	yearHours := map[uint][]entities.CategoryHours{}
+	allHours := map[uint][]entities.CategoryHours{}
+	for _, row := range rows {
+		if row.EarnedHours > 0 || row.PendingHours > 0 {
+			yearHours[row.UserID] = append(yearHours[row.UserID], entities.CategoryHours{
+				CategoryID:   row.CategoryID,
+				CategoryName: row.CategoryName,
+				EarnedHours:  row.EarnedHours,
+				PendingHours: row.PendingHours,
+			})
+		}
+		allHours[row.UserID] = append(allHours[row.UserID], entities.CategoryHours{
+			CategoryID:   row.CategoryID,
+			CategoryName: row.CategoryName,
+			EarnedHours:  row.TotalEarnedHours,
+			PendingHours: row.TotalPendingHours,
+		})
+	}
+	return func(userID, facultyID, branchID, classYear uint) *entities.RequirementEvaluation {
+		set := matchScope(sets, facultyID, branchID, classYear)
+		hours := allHours[userID]
+		if evaluationYear(set, schoolYear) != 0 {
+			hours = yearHours[userID]
+		}
+		evaluation := u.evaluateHours(set, classYear, schoolYear, hours)
+		evaluation.UserID = userID
+		return evaluation
+	}, nil
+}
@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	"RESTAPI/utility"
+	"fmt"
+)
+
+// StaffUsecase งานของเจ้าหน้าที่คณะ สิทธิ์ทั้งหมดจำกัดเฉพาะสาขาในคณะที่ตนเป็นเจ้าหน้าที่
+type StaffUsecase interface {
+	MyFaculties(userID uint) ([]entities.Faculty, error)
+	BranchIDs(userID uint) ([]uint, error)
+	IsStaffOfStudent(userID uint, studentID uint) (bool, error)
+	IsStaffOfEvent(userID uint, eventID uint) (bool, error)
+	OwnsEvent(userID uint, event *entities.EventResponse) (bool, error)
+	GetStudents(userID uint) ([]entities.StudentResponse, error)
+	GetEvents(userID uint) ([]entities.EventResponse, error)
+	GetChecklist(userID uint, eventID uint) ([]entities.MyChecklist, error)
+	GetStudentEvents(userID uint, studentID uint, year uint) ([]entities.MyInside, []entities.MyOutside, error)
+	EditStudent(userID uint, student *entities.Student) error
+	ConfirmAndCheck(userID uint, eventID uint, studentID uint, status bool, comment string) error
+}
+
+type staffUsecase struct {
+	facultyRepo   repository.FacultyRepository
+	branchRepo    repository.BranchRepository
+	userRepo      repository.UserRepository
+	studentRepo   repository.StudentRepository
+	insideRepo    repository.EventInsideRepository
+	organizerRepo repository.OrganizerRepository
+	eventUsecase  EventUsecase
+}
+
+func NewStaffUsecase(facultyRepo repository.FacultyRepository, branchRepo repository.BranchRepository, userRepo repository.UserRepository, studentRepo repository.StudentRepository, insideRepo repository.EventInsideRepository, organizerRepo repository.OrganizerRepository, eventUsecase EventUsecase) StaffUsecase {
+	return &staffUsecase{
+		facultyRepo:   facultyRepo,
+		branchRepo:    branchRepo,
+		userRepo:      userRepo,
+		studentRepo:   studentRepo,
+		insideRepo:    insideRepo,
+		organizerRepo: organizerRepo,
+		eventUsecase:  eventUsecase,
+	}
+}
+
+func (u *staffUsecase) MyFaculties(userID uint) ([]entities.Faculty, error) {
+	return u.facultyRepo.GetFacultiesByStaff(userID)
+}
+
+// BranchIDs สาขาทั้งหมดของทุกคณะที่ผู้ใช้เป็นเจ้าหน้าที่
+func (u *staffUsecase) BranchIDs(userID uint) ([]uint, error) {
+	faculties, err := u.facultyRepo.GetFacultiesByStaff(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faculties: %w", err)
+	}
+	var branchIDs []uint
+	for _, faculty := range faculties {
+		branches, err := u.branchRepo.GetAllBranchesByFaculty(int(faculty.FacultyID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get branches: %w", err)
+		}
+		for _, branch := range branches {
+			branchIDs = append(branchIDs, branch.BranchID)
+		}
+	}
+	return branchIDs, nil
+}
+
+// scope คืนสาขาของเจ้าหน้าที่ ถ้าไม่ได้เป็นเจ้าหน้าที่คณะใดเลยถือว่าไม่มีสิทธิ์
+func (u *staffUsecase) scope(userID uint) ([]uint, error) {
+	branchIDs, err := u.BranchIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(branchIDs) == 0 {
+		return nil, fmt.Errorf("%w: you are not staff of any faculty", ErrPermissionDenied)
+	}
+	return branchIDs, nil
+}
+
+// facultyTeachers อาจารย์ที่เป็นเจ้าหน้าที่ของคณะเดียวกับผู้ใช้ (รวมตัวผู้ใช้เอง)
+func (u *staffUsecase) facultyTeachers(userID uint) (map[uint]bool, error) {
+	faculties, err := u.facultyRepo.GetFacultiesByStaff(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faculties: %w", err)
+	}
+	teachers := map[uint]bool{}
+	for _, faculty := range faculties {
+		staff, err := u.facultyRepo.GetFacultyStaff(faculty.FacultyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get faculty staff: %w", err)
+		}
+		for _, member := range staff {
+			teachers[member.UserID] = true
+		}
+	}
+	return teachers, nil
+}
+
+// ownsEvent กิจกรรมเป็นของคณะเมื่อจำกัดผู้เข้าร่วมไว้ที่สาขาในคณะ หรือผู้สร้างหรือผู้จัดร่วมเป็นเจ้าหน้าที่ของคณะ
+// AllowAllBranch บอกเพียงว่าใครเข้าร่วมได้ ไม่ได้ทำให้กิจกรรมเป็นของทุกคณะ
+func (u *staffUsecase) ownsEvent(event *entities.EventResponse, branchIDs []uint, teachers map[uint]bool) (bool, error) {
+	if !event.AllowAllBranch {
+		for _, branchID := range event.BranchIDs {
+			if utility.ContainsUint(branchIDs, branchID) {
+				return true, nil
+			}
+		}
+	}
+	if teachers[event.Creator.UserID] {
+		return true, nil
+	}
+	organizers, err := u.organizerRepo.GetOrganizers(event.EventID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get organizers: %w", err)
+	}
+	for _, organizer := range organizers {
+		if teachers[organizer.UserID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// OwnsEvent กิจกรรมเป็นของคณะที่ผู้ใช้เป็นเจ้าหน้าที่หรือไม่ ผู้ที่ไม่ได้เป็นเจ้าหน้าที่คณะใดไม่เป็นเจ้าของกิจกรรมใด
+func (u *staffUsecase) OwnsEvent(userID uint, event *entities.EventResponse) (bool, error) {
+	branchIDs, err := u.BranchIDs(userID)
+	if err != nil {
+		return false, err
+	}
+	if len(branchIDs) == 0 {
+		return false, nil
+	}
+	teachers, err := u.facultyTeachers(userID)
+	if err != nil {
+		return false, err
+	}
+	return u.ownsEvent(event, branchIDs, teachers)
+}
+
+func (u *staffUsecase) IsStaffOfStudent(userID uint, studentID uint) (bool, error) {
+	branchIDs, err := u.BranchIDs(userID)
+	if err != nil {
+		return false, err
+	}
+	student, err := u.userRepo.GetStudentByUserID(studentID)
+	if err != nil {
+		return false, fmt.Errorf("%w: student not found", ErrNotFound)
+	}
+	return utility.ContainsUint(branchIDs, student.BranchId), nil
+}
+
+func (u *staffUsecase) IsStaffOfEvent(userID uint, eventID uint) (bool, error) {
+	event, err := u.eventUsecase.GetEventByID(eventID)
+	if err != nil {
+		return false, fmt.Errorf("event not found")
+	}
+	return u.OwnsEvent(userID, event)
+}
+
+func (u *staffUsecase) GetStudents(userID uint) ([]entities.StudentResponse, error) {
+	branchIDs, err := u.scope(userID)
+	if err != nil {
+		return nil, err
+	}
+	students, err := u.studentRepo.GetStudentsByBranches(branchIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := []entities.StudentResponse{}
+	for _, student := range students {
+		res = append(res, mapStudentResponse(student))
+	}
+	return res, nil
+}
+
+func (u *staffUsecase) GetEvents(userID uint) ([]entities.EventResponse, error) {
+	branchIDs, err := u.scope(userID)
+	if err != nil {
+		return nil, err
+	}
+	teachers, err := u.facultyTeachers(userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := u.eventUsecase.GetAllEvent()
+	if err != nil {
+		return nil, err
+	}
+	res := []entities.EventResponse{}
+	for i := range events {
+		owned, err := u.ownsEvent(&events[i], branchIDs, teachers)
+		if err != nil {
+			return nil, err
+		}
+		if owned {
+			res = append(res, events[i])
+		}
+	}
+	return res, nil
+}
+
+// GetChecklist รายชื่อผู้เข้าร่วมกิจกรรม เฉพาะนักศึกษาในคณะของเจ้าหน้าที่
+func (u *staffUsecase) GetChecklist(userID uint, eventID uint) ([]entities.MyChecklist, error) {
+	branchIDs, err := u.scope(userID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := u.eventUsecase.GetEventByID(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+	teachers, err := u.facultyTeachers(userID)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := u.ownsEvent(event, branchIDs, teachers)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, fmt.Errorf("%w: event is not in your faculty", ErrPermissionDenied)
+	}
+	checklist, err := u.insideRepo.MyChecklist(userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	res := []entities.MyChecklist{}
+	for _, inside := range checklist {
+		if utility.ContainsUint(branchIDs, inside.Student.BranchId) {
+			res = append(res, mapChecklist(inside))
+		}
+	}
+	return res, nil
+}
+
+func (u *staffUsecase) GetStudentEvents(userID uint, studentID uint, year uint) ([]entities.MyInside, []entities.MyOutside, error) {
+	ok, err := u.IsStaffOfStudent(userID, studentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: student is not in your faculty", ErrPermissionDenied)
+	}
+	return u.eventUsecase.AllMyEventThisYear(studentID, year)
+}
+
+// EditStudent แก้ไขข้อมูลนักศึกษาในคณะ และย้ายได้เฉพาะสาขาภายในคณะเท่านั้น
+func (u *staffUsecase) EditStudent(userID uint, student *entities.Student) error {
+	branchIDs, err := u.scope(userID)
+	if err != nil {
+		return err
+	}
+	current, err := u.userRepo.GetStudentByUserID(student.UserID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	if !utility.ContainsUint(branchIDs, current.BranchId) {
+		return fmt.Errorf("%w: student is not in your faculty", ErrPermissionDenied)
+	}
+	if !utility.ContainsUint(branchIDs, student.BranchId) {
+		return fmt.Errorf("%w: branch %d is not in your faculty", ErrPermissionDenied, student.BranchId)
+	}
+	return u.studentRepo.EditStudentByID(student)
+}
+
+func (u *staffUsecase) ConfirmAndCheck(userID uint, eventID uint, studentID uint, status bool, comment string) error {
+	branchIDs, err := u.scope(userID)
+	if err != nil {
+		return err
+	}
+	event, err := u.eventUsecase.GetEventByID(eventID)
+	if err != nil {
+		return fmt.Errorf("event not found")
+	}
+	teachers, err := u.facultyTeachers(userID)
+	if err != nil {
+		return err
+	}
+	owned, err := u.ownsEvent(event, branchIDs, teachers)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return fmt.Errorf("%w: event is not in your faculty", ErrPermissionDenied)
+	}
+	student, err := u.userRepo.GetStudentByUserID(studentID)
+	if err != nil {
+		return fmt.Errorf("student not found")
+	}
+	if !utility.ContainsUint(branchIDs, student.BranchId) {
+		return fmt.Errorf("%w: student is not in your faculty", ErrPermissionDenied)
+	}
+	joined, err := u.insideRepo.IsUserJoinedEvent(eventID, studentID)
+	if err != nil {
+		return err
+	}
+	if !joined {
+		return fmt.Errorf("user is not a member of this event")
+	}
+	return u.insideRepo.UpdateEventStatusAndComment(eventID, studentID, userID, status, comment)
+}
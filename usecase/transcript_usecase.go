@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"RESTAPI/domain/entities"
+	"RESTAPI/domain/repository"
+	filesystem "RESTAPI/utility/fileSystem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TranscriptUsecase ใบสรุปชั่วโมงกิจกรรม (ภายในและภายนอก) ของนักศึกษา รายปีการศึกษาหรือตลอดหลักสูตร
+type TranscriptUsecase interface {
+	Transcript(ownerID uint, year uint, requesterID uint, role string) ([]byte, string, error)
+}
+
+type transcriptUsecase struct {
+	userRepo           repository.UserRepository
+	eventUsecase       EventUsecase
+	staffUsecase       StaffUsecase
+	documentUsecase    DocumentUsecase
+	requirementUsecase RequirementUsecase
+	renderer           *filesystem.PDFRenderer
+}
+
+func NewTranscriptUsecase(userRepo repository.UserRepository, eventUsecase EventUsecase, staffUsecase StaffUsecase, documentUsecase DocumentUsecase, requirementUsecase RequirementUsecase, renderer *filesystem.PDFRenderer) TranscriptUsecase {
+	return &transcriptUsecase{
+		userRepo:           userRepo,
+		eventUsecase:       eventUsecase,
+		staffUsecase:       staffUsecase,
+		documentUsecase:    documentUsecase,
+		requirementUsecase: requirementUsecase,
+		renderer:           renderer,
+	}
+}
+
+// Transcript year เป็น 0 คือตลอดหลักสูตร ออกให้เจ้าของ admin และเจ้าหน้าที่คณะของนักศึกษา
+func (u *transcriptUsecase) Transcript(ownerID uint, year uint, requesterID uint, role string) ([]byte, string, error) {
+	if requesterID != ownerID && role != "admin" {
+		allowed, err := u.staffUsecase.IsStaffOfStudent(requesterID, ownerID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !allowed {
+			return nil, "", fmt.Errorf("%w: student is not in your faculty", ErrPermissionDenied)
+		}
+	}
+	student, err := u.userRepo.GetStudentByUserID(ownerID)
+	if err != nil {
+		return nil, "", fmt.Errorf("student not found")
+	}
+	insideEvents, outsideEvents, err := u.eventUsecase.AllMyEventThisYear(ownerID, year)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// จัดกลุ่มตามปีการศึกษา กิจกรรมภายในนับชั่วโมงเมื่อได้รับการรับรองแล้ว กิจกรรมภายนอกนับตามที่บันทึกพร้อมผู้รับรอง
+	// กิจกรรมที่ไม่ผ่านการรับรองแสดงในรายการแต่ไม่นับชั่วโมง
+	years := map[uint]*filesystem.TranscriptYear{}
+	yearOf := func(schoolYear uint) *filesystem.TranscriptYear {
+		if years[schoolYear] == nil {
+			years[schoolYear] = &filesystem.TranscriptYear{SchoolYear: schoolYear}
+		}
+		return years[schoolYear]
+	}
+	categories := map[string]*filesystem.TranscriptCategory{}
+	categoryOf := func(name string) *filesystem.TranscriptCategory {
+		if name == "" {
+			name = uncategorized
+		}
+		if categories[name] == nil {
+			categories[name] = &filesystem.TranscriptCategory{Name: name}
+		}
+		return categories[name]
+	}
+	for _, event := range insideEvents {
+		y := yearOf(event.SchoolYear)
+		c := categoryOf(event.CategoryName)
+		var status string
+		switch {
+		case event.Status:
+			status = "รับรองแล้ว"
+			y.EarnedHours += event.WorkingHour
+			c.EarnedHours += event.WorkingHour
+		case event.Rejected:
+			status = "ไม่ผ่านการรับรอง"
+		default:
+			status = "รอรับรอง"
+			y.PendingHours += event.WorkingHour
+			c.PendingHours += event.WorkingHour
+		}
+		y.Activities = append(y.Activities, filesystem.TranscriptActivity{
+			Name:   event.EventName,
+			Kind:   "ภายใน",
+			Date:   event.StartDate,
+			Hours:  event.WorkingHour,
+			Status: status,
+		})
+	}
+	for _, event := range outsideEvents {
+		y := yearOf(event.SchoolYear)
+		y.EarnedHours += event.WorkingHour
+		categoryOf(event.CategoryName).EarnedHours += event.WorkingHour
+		y.Activities = append(y.Activities, filesystem.TranscriptActivity{
+			Name:   event.EventName,
+			Kind:   "ภายนอก",
+			Date:   event.StartDate,
+			Hours:  event.WorkingHour,
+			Status: "มีผู้รับรอง",
+		})
+	}
+
+	data := filesystem.TranscriptData{
+		StudentName: student.TitleName + student.FirstName + " " + student.LastName,
+		StudentCode: student.Code,
+		BranchName:  student.Branch.BranchName,
+		FacultyName: student.Branch.Faculty.FacultyName,
+		IssuedAt:    time.Now(),
+	}
+	for _, y := range years {
+		data.Years = append(data.Years, *y)
+		data.EarnedHours += y.EarnedHours
+		data.PendingHours += y.PendingHours
+	}
+	sort.Slice(data.Years, func(i, j int) bool {
+		return data.Years[i].SchoolYear < data.Years[j].SchoolYear
+	})
+	// เรียงหมวดหมู่ตามชื่อ กิจกรรมที่ไม่ระบุหมวดหมู่อยู่ท้ายสุด
+	for _, c := range categories {
+		data.Categories = append(data.Categories, *c)
+	}
+	sort.Slice(data.Categories, func(i, j int) bool {
+		if (data.Categories[i].Name == uncategorized) != (data.Categories[j].Name == uncategorized) {
+			return data.Categories[j].Name == uncategorized
+		}
+		return data.Categories[i].Name < data.Categories[j].Name
+	})
+
+	fileName := fmt.Sprintf("transcript_%s_%d.pdf", student.Code, year)
+	if year != 0 {
+		data.Period = fmt.Sprintf("ปีการศึกษา %d", year)
+	} else {
+		data.Period = "ตลอดหลักสูตร"
+		if len(data.Years) > 0 {
+			data.Period += fmt.Sprintf(" (ปีการศึกษา %d-%d)", data.Years[0].SchoolYear, data.Years[len(data.Years)-1].SchoolYear)
+		}
+		fileName = fmt.Sprintf("transcript_%s_all.pdf", student.Code)
+	}
+
+	// ผลการประเมินใช้เกณฑ์ที่ตรงกับคณะ สาขา และชั้นปีของนักศึกษา เหมือนกับ /requirements/evaluate
+	evaluation, err := u.requirementUsecase.EvaluateStudent(student, year)
+	if err != nil {
+		return nil, "", err
+	}
+	missing := []string{}
+	for _, rule := range evaluation.Rules {
+		if rule.Kind == entities.RequirementTotal {
+			data.RequiredHours = rule.RequiredHours
+		}
+		if !rule.Met {
+			missing = append(missing, rule.Explanation)
+		}
+	}
+	switch {
+	case len(evaluation.Rules) == 0:
+	case evaluation.Met:
+		data.Result = "ผ่านเกณฑ์"
+	default:
+		data.Result = strings.Join(missing, ", ")
+	}
+	if len(evaluation.Rules) > 0 && evaluation.SchoolYear != year {
+		data.Result += " (เกณฑ์ตลอดหลักสูตร)"
+	}
+
+	document := &entities.IssuedDocument{
+		Type:       entities.DocumentTranscript,
+		OwnerID:    ownerID,
+		SchoolYear: year,
+		Title:      "ใบสรุปชั่วโมงการเข้าร่วมกิจกรรม " + data.Period,
+		Details:    fmt.Sprintf("ชั่วโมงที่ได้รับ %d ชั่วโมง รอการรับรอง %d ชั่วโมง ชั่วโมงที่กำหนด %d ชั่วโมง", data.EarnedHours, data.PendingHours, data.RequiredHours),
+		IssuedBy:   requesterID,
+	}
+	pdf, err := u.documentUsecase.Issue(document, filesystem.TemplateTranscript, func(verification *filesystem.Verification) ([]byte, error) {
+		data.Verification = verification
+		return u.renderer.Transcript(data)
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating PDF: %w", err)
+	}
+	return pdf, fileName, nil
+}
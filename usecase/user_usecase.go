@@ -123,24 +123,27 @@ func (u *userUsecase) GetAllStudent() ([]entities.StudentResponse, error) {
 
 	allStudentRes := []entities.StudentResponse{}
 	for _, student := range allStudent {
-		studentRes := entities.StudentResponse{
-			UserID:     student.UserID,
-			TitleName:  student.TitleName,
-			FirstName:  student.FirstName,
-			LastName:   student.LastName,
-			Phone:      student.Phone,
-			Code:       student.Code,
-			BranchID:   student.BranchId,
-			BranchName: student.Branch.BranchName,
-			FacultyID:  student.Branch.Faculty.FacultyID,
-			FacultyName: student.Branch.Faculty.FacultyName,
-		}
-		allStudentRes = append(allStudentRes, studentRes)
+		allStudentRes = append(allStudentRes, mapStudentResponse(student))
 	}
 
 	return allStudentRes, nil
 }
 
+func mapStudentResponse(student entities.Student) entities.StudentResponse {
+	return entities.StudentResponse{
+		UserID:      student.UserID,
+		TitleName:   student.TitleName,
+		FirstName:   student.FirstName,
+		LastName:    student.LastName,
+		Phone:       student.Phone,
+		Code:        student.Code,
+		BranchID:    student.BranchId,
+		BranchName:  student.Branch.BranchName,
+		FacultyID:   student.Branch.Faculty.FacultyID,
+		FacultyName: student.Branch.Faculty.FacultyName,
+	}
+}
+
 func (u *userUsecase) GetAllTeacher() ([]entities.Teacher, error) {
 	return u.teacherRepo.GetAllTeacher()
 }
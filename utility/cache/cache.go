@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type item struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Cache เก็บผลลัพธ์ในหน่วยความจำตามอายุ ttl ถ้า ttl เป็น 0 จะไม่เก็บ (โหลดใหม่ทุกครั้ง)
+type Cache struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	items map[string]item
+}
+
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, items: map[string]item{}}
+}
+
+// Get คืนค่าที่เก็บไว้ของ key ถ้าไม่มีหรือหมดอายุแล้วจะเรียก load และเก็บผลไว้ (ไม่เก็บเมื่อ load ผิดพลาด)
+func (c *Cache) Get(key string, load func() (interface{}, error)) (interface{}, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+	now := time.Now()
+	c.mu.Lock()
+	if cached, ok := c.items[key]; ok && now.Before(cached.expiresAt) {
+		c.mu.Unlock()
+		return cached.value, nil
+	}
+	c.mu.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, cached := range c.items {
+		if !now.Before(cached.expiresAt) {
+			delete(c.items, k)
+		}
+	}
+	c.items[key] = item{value: value, expiresAt: now.Add(c.ttl)}
+	return value, nil
+}
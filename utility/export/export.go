@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// รูปแบบไฟล์ที่ส่งออกได้
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer เขียนตารางทีละแถวลงปลายทางโดยไม่เก็บทั้งไฟล์ไว้ในหน่วยความจำ ต้องเรียก Close เมื่อเขียนครบ
+// หรือ Abort เมื่อเขียนไม่สำเร็จ เพื่อคืนทรัพยากรโดยไม่เขียนไฟล์ที่ไม่ครบลงปลายทาง
+type Writer interface {
+	Write(row []interface{}) error
+	Close() error
+	Abort()
+}
+
+// ContentType ชนิดของเนื้อหาสำหรับ header ของ response
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ValidFormat ค่าว่างถือเป็น csv
+func ValidFormat(format string) (string, error) {
+	switch format {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q", format)
+}
+
+// NewWriter sheet คือชื่อแผ่นงานของไฟล์ xlsx (csv ไม่ใช้)
+func NewWriter(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheet)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// formulaPrefixes อักขระขึ้นต้นที่ทำให้โปรแกรมตารางคำนวณตีความข้อความเป็นสูตร
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula ใส่ ' หน้าข้อความที่ขึ้นต้นด้วยอักขระของสูตร เช่นชื่อหรือความเห็นที่ผู้ใช้กรอก
+// เพื่อให้แสดงเป็นข้อความแทนการคำนวณเมื่อเปิดหรือแก้ไขใน Excel ค่าที่เป็นตัวเลขไม่ถูกแก้ไข
+func escapeFormula(row []interface{}) []interface{} {
+	escaped := make([]interface{}, len(row))
+	for i, value := range row {
+		if s, ok := value.(string); ok && s != "" && strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+			value = "'" + s
+		}
+		escaped[i] = value
+	}
+	return escaped
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// newCSVWriter ใส่ BOM ไว้หน้าไฟล์ เพื่อให้ Excel เปิดภาษาไทยแบบ UTF-8 ได้ถูกต้อง
+func newCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) Write(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, value := range escapeFormula(row) {
+		if value != nil {
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Abort csv ไม่มีทรัพยากรที่ต้องคืน แถวที่ส่งไปแล้วไม่สามารถเรียกคืนได้
+func (c *csvWriter) Abort() {}
+
+// xlsxWriter ใช้ StreamWriter ของ excelize ซึ่งพักแถวไว้ในไฟล์ชั่วคราวเมื่อข้อมูลมีขนาดใหญ่
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, sheet string) (Writer, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{w: w, file: file, stream: stream}, nil
+}
+
+func (x *xlsxWriter) Write(row []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, escapeFormula(row))
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.w)
+}
+
+// Abort ลบไฟล์ชั่วคราวของ StreamWriter โดยไม่เขียนไฟล์ลงปลายทาง
+func (x *xlsxWriter) Abort() {
+	x.file.Close()
+}
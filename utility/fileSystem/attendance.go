@@ -0,0 +1,51 @@
+package filesystem
+
+import (
+	"RESTAPI/utility"
+	"fmt"
+	"time"
+)
+
+// TemplateAttendanceSheet ชื่อแม่แบบของใบลงชื่อเข้าร่วมกิจกรรม
+const TemplateAttendanceSheet = "attendance_sheet"
+
+// AttendanceRow ผู้เข้าร่วมหนึ่งคนในใบลงชื่อ
+type AttendanceRow struct {
+	Code   string
+	Name   string
+	Branch string
+}
+
+// AttendanceData ข้อมูลใบลงชื่อของกิจกรรมภายใน ผู้เรียกเรียงลำดับ Participants เอง
+type AttendanceData struct {
+	EventName    string
+	Location     string
+	StartDate    time.Time
+	WorkingHour  uint
+	Organizer    string
+	Participants []AttendanceRow
+}
+
+// AttendanceSheet สร้างใบลงชื่อพร้อมช่องเวลามา เวลากลับ และลายมือชื่อ
+func (r *PDFRenderer) AttendanceSheet(data AttendanceData) ([]byte, error) {
+	doc := Document{
+		Fields: map[string]string{
+			"event_name":   data.EventName,
+			"location":     data.Location,
+			"date":         utility.FormatToThaiDate(data.StartDate),
+			"time":         utility.FormatToThaiTimeRange(data.StartDate, data.WorkingHour),
+			"hours":        fmt.Sprint(data.WorkingHour),
+			"organizer":    data.Organizer,
+			"participants": fmt.Sprint(len(data.Participants)),
+		},
+	}
+	for i, participant := range data.Participants {
+		doc.Rows = append(doc.Rows, map[string]string{
+			"no":     fmt.Sprint(i + 1),
+			"code":   participant.Code,
+			"name":   participant.Name,
+			"branch": participant.Branch,
+		})
+	}
+	return r.Render(TemplateAttendanceSheet, doc)
+}
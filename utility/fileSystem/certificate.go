@@ -0,0 +1,45 @@
+package filesystem
+
+import (
+	"RESTAPI/utility"
+	"fmt"
+	"time"
+)
+
+// TemplateCertificate ชื่อแม่แบบของเกียรติบัตรการเข้าร่วมกิจกรรมภายใน
+const TemplateCertificate = "certificate"
+
+// CertificateData ข้อมูลที่พิมพ์บนเกียรติบัตรหนึ่งใบ
+type CertificateData struct {
+	StudentName       string
+	StudentCode       string
+	FacultyName       string
+	EventName         string
+	Location          string
+	StartDate         time.Time
+	WorkingHour       uint
+	CertifierName     string
+	CertifierPosition string
+	IssuedAt          time.Time
+	Verification      *Verification
+}
+
+// Certificate สร้างเกียรติบัตรหนึ่งใบจากแม่แบบ certificate
+func (r *PDFRenderer) Certificate(data CertificateData) ([]byte, error) {
+	doc := Document{
+		Fields: map[string]string{
+			"student_name":       data.StudentName,
+			"student_code":       data.StudentCode,
+			"faculty":            data.FacultyName,
+			"event_name":         data.EventName,
+			"location":           data.Location,
+			"date":               utility.FormatToThaiDate(data.StartDate),
+			"hours":              fmt.Sprint(data.WorkingHour),
+			"certifier_name":     data.CertifierName,
+			"certifier_position": data.CertifierPosition,
+			"issued_date":        utility.FormatToThaiDate(data.IssuedAt),
+		},
+	}
+	data.Verification.apply(doc.Fields)
+	return r.Render(TemplateCertificate, doc)
+}
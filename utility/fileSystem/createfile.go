@@ -1,196 +1,78 @@
 package filesystem
 
 import (
-	// "RESTAPI/usecase"
 	"RESTAPI/domain/entities"
-	"bytes"
+	"RESTAPI/utility"
 	"fmt"
-	"log"
-	"os"
-	"time"
 
 	"github.com/signintech/gopdf"
 )
 
-func CreatePDF(data entities.OutsideResponse) ([]byte, string, error) {
-    pdf := gopdf.GoPdf{}
-	// กำหนดการตั้งค่า PDF เช่น ขนาดหน้ากระดาษ A4
-	landscapeSize := gopdf.Rect{W: 841.89, H: 595.28}
-	pdf.Start(gopdf.Config{PageSize: landscapeSize})
-	pdf.AddPage()
+// TemplateOutsideForm ชื่อแม่แบบของแบบบันทึกกิจกรรมภายนอก
+const TemplateOutsideForm = "outside_form"
 
-    watermark(&pdf,landscapeSize)
-
-	err := pdf.AddTTFFont("THSarabunNew", "./utility/fileSystem/assets/THSarabunNew/THSarabunNew.ttf")
-	if err != nil {
-        log.Fatalf("Error adding font: %s", err)
+// OutsideForm สร้างแบบบันทึกกิจกรรมภายนอกของนักศึกษาหนึ่งคน รองรับหลายกิจกรรมในฟอร์มเดียว
+// เลย์เอาต์อยู่ในแม่แบบ outside_form ถ้าแถวเกินหน้าจะขึ้นหน้าใหม่ ช่องรูปภาพและลายเซ็นอยู่หน้าสุดท้าย
+// verification เป็น nil ได้ถ้าไม่ต้องการพิมพ์ QR code
+func (r *PDFRenderer) OutsideForm(records []entities.OutsideResponse, verification *Verification) ([]byte, string, error) {
+	if len(records) == 0 {
+		return nil, "", fmt.Errorf("no outside activities to print")
 	}
-    
-	err = pdf.AddTTFFont("THSarabunNewBold", "./utility/fileSystem/assets/THSarabunNew/THSarabunNew Bold.ttf")
+	tpl, err := r.Template(TemplateOutsideForm)
 	if err != nil {
-        log.Fatalf("Error adding font: %s", err)
+		return nil, "", err
 	}
-    
-    header(&pdf,data)
-
-	table(&pdf,data)
 
-	drawImage(&pdf)
-
-   
-   // สร้างไฟล์ PDF โดยการเขียนลงในไฟล์ชั่วคราว (temporary file)
-	tempFile, err := os.CreateTemp("", "tempfile-*.pdf")
-	if err != nil {
-		return nil, "", fmt.Errorf("error creating temporary file: %v", err)
+	// ถ้าทุกกิจกรรมมีผู้รับรองคนเดียวกันจะพิมพ์ชื่อไว้ให้
+	first := records[0]
+	intendant := first.Intendant
+	for _, data := range records[1:] {
+		if data.Intendant != intendant {
+			intendant = ""
+			break
+		}
 	}
-	defer tempFile.Close()
-
-	// เขียน PDF ลงในไฟล์ชั่วคราว
-	err = pdf.WritePdf(tempFile.Name())
-	if err != nil {
-		return nil, "", fmt.Errorf("error creating PDF: %v", err)
+	doc := Document{
+		Fields: map[string]string{
+			"school_year":  fmt.Sprint(first.SchoolYear),
+			"student_name": first.Student.TitleName + first.Student.FirstName + " " + first.Student.LastName,
+			"phone":        first.Student.Phone,
+			"student_code": first.Student.Code,
+			"branch":       first.Student.BranchName,
+			"faculty":      first.Student.FacultyName,
+			"intendant":    intendant,
+		},
 	}
-
-	// อ่านข้อมูล PDF จากไฟล์ชั่วคราวไปยัง memory buffer
-	var buf bytes.Buffer
-	_, err = buf.ReadFrom(tempFile)
-	if err != nil {
-		return nil, "", fmt.Errorf("error reading PDF file to memory buffer: %v", err)
+	verification.apply(doc.Fields)
+	for _, data := range records {
+		doc.Rows = append(doc.Rows, map[string]string{
+			"event_name": data.EventName,
+			"date":       utility.FormatToThaiShortDate(data.StartDate),
+			"location":   data.Location,
+			"time":       utility.FormatToThaiTimeRange(data.StartDate, data.WorkingHour),
+			"hours":      fmt.Sprint(data.WorkingHour),
+		})
 	}
 
-	// คืนค่าเป็น []byte และชื่อไฟล์
-	// fileName := fmt.Sprintf("แบบฟอร์มบันทึกกิจกรรม.pdf")
-	fileName := "แบบฟอร์มบันทึกกิจกรรม.pdf"
-	return buf.Bytes(), fileName, nil
-
-}
-
-func watermark(pdf *gopdf.GoPdf,landscapeSize gopdf.Rect){
-    imageWidth := 247.8
-	imageHeight := 464.1
-
-	pageWidth, pageHeight := landscapeSize.W, landscapeSize.H
-	x := (pageWidth - imageWidth) / 2
-	y := (pageHeight - imageHeight) / 2
-
-	err := pdf.Image("./utility/fileSystem/assets/image/bg2.png", x, y, &gopdf.Rect{W: imageWidth, H: imageHeight})
+	data, err := r.Render(TemplateOutsideForm, doc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, "", err
 	}
+	return data, tpl.FileName, nil
 }
 
-func header(pdf *gopdf.GoPdf,data entities.OutsideResponse){
-    year:=CheckYear()
-
-	pdf.SetFont("THSarabunNewBold", "", 20)
-	pdf.SetXY(130, 50)
-	pdf.Cell(nil, "แบบบันทึกการเข้าร่วมกิจกรรม/โครงการจิตอาสา ประจำปีการศึกษา............... มหาวิทยาลัยเทคโนโลยีราชมงคลอีสาน")
-
-	pdf.SetFont("THSarabunNewBold", "", 18)
-	pdf.SetXY(150, 85)
-	pdf.Cell(nil, "ชื่อ-สกุล................................................................... หมายเลขโทรศัพท์............................ รหัสนักศึกษา...............................")
-
-    
-	pdf.SetFont("THSarabunNewBold", "", 16)
-	pdf.SetXY(510, 49)
-	pdf.Cell(nil, year)
-
-	pdf.SetXY(196, 82)
-	pdf.Cell(nil, data.Student.TitleName+data.Student.FirstName+" "+data.Student.LastName)
-    
-	pdf.SetXY(500, 82)
-	pdf.Cell(nil,data.Student.Phone)
-
-	pdf.SetXY(655, 82)
-	pdf.Cell(nil, data.Student.Code)
-
-	pdf.SetFont("THSarabunNewBold", "", 18)
-	pdf.SetXY(180, 120)
-	pdf.Cell(nil, "สาขา..................................................................... คณะ..................................................................................")
-
-	pdf.SetFont("THSarabunNewBold", "", 16)
-	pdf.SetXY(216, 117)
-	pdf.Cell(nil, data.Student.BranchName)
-
-	pdf.SetXY(456, 117)
-	pdf.Cell(nil, data.Student.FacultyName)
-
-    err := pdf.Image("./utility/fileSystem/assets/image/logo.png", 40, 30, &gopdf.Rect{W: 53.1, H: 99.45})
-	if err != nil {
-		log.Fatal(err)
+// fitText ตัดข้อความให้พอดีความกว้างของช่องตาราง
+func fitText(pdf *gopdf.GoPdf, text string, width float64) string {
+	if w, err := pdf.MeasureTextWidth(text); err != nil || w <= width {
+		return text
 	}
-	err = pdf.Image("./utility/fileSystem/assets/image/logo2.png", 700, 80, &gopdf.Rect{W: 100, H: 100})
-	if err != nil {
-		log.Fatal(err)
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "..."
+		if w, err := pdf.MeasureTextWidth(candidate); err == nil && w <= width {
+			return candidate
+		}
 	}
+	return ""
 }
-
-
-func table(pdf *gopdf.GoPdf,data entities.OutsideResponse) {
-	tableStartY := 180.0
-	marginLeft := 51.0
-	table := pdf.NewTableLayout(marginLeft, tableStartY, 30, 1)
-
-
-	pdf.SetFont("THSarabunNewBold", "", 16)
-	table.AddColumn("โครงการ/กิจกรรมจิตอาสา", 260, "left")
-	table.AddColumn("วันเดือนปี ที่เข้าร่วม", 100, "center")
-	table.AddColumn("สถานที่", 150, "center")
-	table.AddColumn("เวลามา-เวลากลับ", 150, "center")
-	table.AddColumn("จำนวนชั่งโมง", 80, "center")
-	table.AddRow([]string{data.EventName, "9 ม.ค. 2568",data.Location, "10:00-16:00", fmt.Sprint(data.WorkingHour)})
-	table.DrawTable()
-
-    pdf.SetFont("THSarabunNewBold", "", 18)
-
-    lineText := "................................................................"
-    topText := "(  "+data.Intendant+"  )"
-    bottomText := "ผู้รับรองการเข้าร่วมโครงการ"
-
-    lineTextWidth,_ := pdf.MeasureTextWidth(lineText)
-    topTextWidth, _ := pdf.MeasureTextWidth(topText)
-    bottomTextWidth, _ := pdf.MeasureTextWidth(bottomText)
-
-	pdf.SetXY(570, 360)
-	pdf.Cell(nil,lineText)
-
-    newX := 570 + (lineTextWidth-topTextWidth)/2
-
-    pdf.SetXY(newX, 390)
-    pdf.Cell(nil, topText)
-
-    // คำนวณตำแหน่ง X ใหม่สำหรับข้อความด้านล่าง
-    newX = 570 + (lineTextWidth-bottomTextWidth)/2
-
-    // ตั้งค่าตำแหน่งใหม่สำหรับข้อความด้านล่าง
-    pdf.SetXY(newX, 420)
-    pdf.Cell(nil, bottomText)
-
-}
-
-func drawImage(pdf *gopdf.GoPdf) {
-	pdf.SetStrokeColor(0, 0, 0)
-	pdf.SetLineWidth(0.5)
-	pdf.RectFromUpperLeftWithStyle(50, 260, 464, 290, "D")
-
-	pdf.SetFont("THSarabunNewBold", "", 16)
-	pdf.SetXY(265, 390)
-	pdf.Cell(nil, "ใส่รูปภาพ")
-    
-
-}
-
-func CheckYear() string {
-    year:=""
-    currentTime := time.Now()
-    formattedTime := currentTime.Format("2006-01-02 15:04:05")
-    fmt.Println("Formatted Time:", formattedTime)
-    currentMonth := int(currentTime.Month())
-    currentYear := currentTime.Year()
-    if currentMonth >=6 && currentMonth<=12 {
-        year = fmt.Sprint(currentYear+543)
-    }else{
-        year = fmt.Sprint(currentYear+542)
-    }
-    return year
-}
\ No newline at end of file
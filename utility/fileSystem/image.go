@@ -0,0 +1,203 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// รหัสข้อผิดพลาดของไฟล์รูปภาพ
+const (
+	CodeUnsupportedType = "unsupported_file_type"
+	CodeInvalidImage    = "invalid_image"
+	CodeImageTooLarge   = "image_too_large"
+)
+
+const (
+	// จำกัดจำนวนพิกเซลก่อน decode กันรูปที่บีบอัดไว้เล็กแต่ขยายแล้วใช้หน่วยความจำมหาศาล
+	maxImagePixels = 40_000_000
+	thumbnailSize  = 320
+)
+
+// ProcessedImage รูปที่ถูก encode ใหม่ (ไม่มี EXIF/metadata) พร้อม thumbnail แบบ JPEG
+type ProcessedImage struct {
+	Data        []byte
+	Thumbnail   []byte
+	ContentType string
+	Ext         string
+}
+
+// ProcessImage decode รูป JPEG/PNG แล้ว encode ใหม่ ซึ่งตัด EXIF (เช่นพิกัด GPS) ทิ้งทั้งหมด
+// ทิศทางของรูปจาก EXIF จะถูกหมุนให้ถูกต้องก่อน เพราะ metadata นั้นจะหายไปด้วย
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalid(CodeInvalidImage, "image file is corrupted or unsupported")
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, invalid(CodeUnsupportedType, "only JPEG and PNG images are allowed")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, invalid(CodeImageTooLarge, "image dimensions %dx%d are too large", cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalid(CodeInvalidImage, "image file is corrupted or unsupported")
+	}
+
+	res := &ProcessedImage{}
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+		res.ContentType, res.Ext = "image/jpeg", "jpg"
+	} else {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		res.ContentType, res.Ext = "image/png", "png"
+	}
+	res.Data = buf.Bytes()
+
+	thumb, err := makeThumbnail(img)
+	if err != nil {
+		return nil, err
+	}
+	res.Thumbnail = thumb
+	return res, nil
+}
+
+// makeThumbnail ย่อรูปให้ด้านยาวไม่เกิน thumbnailSize และ encode เป็น JPEG
+func makeThumbnail(img image.Image) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > thumbnailSize || h > thumbnailSize {
+		if w >= h {
+			w, h = thumbnailSize, h*thumbnailSize/w
+		} else {
+			w, h = w*thumbnailSize/h, thumbnailSize
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	// พื้นหลังขาวสำหรับ PNG ที่โปร่งใส เพราะ JPEG ไม่มี alpha
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation อ่านค่า Orientation (tag 0x0112) จาก APP1 Exif คืน 1 ถ้าไม่พบ
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// SOS หรือ EOI แปลว่าผ่านส่วน header ไปแล้ว
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation หมุน/กลับรูปตามค่า EXIF Orientation 1-8
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,106 @@
+package filesystem
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+
+	"github.com/signintech/gopdf"
+)
+
+// ฟอนต์และรูปภาพฝังไว้ในไฟล์ binary ไม่ต้องพึ่ง working directory ตอนรัน
+//
+//go:embed assets/THSarabunNew/THSarabunNew.ttf "assets/THSarabunNew/THSarabunNew Bold.ttf" assets/image/*.png
+var assets embed.FS
+
+var rendererFonts = map[string]string{
+	"THSarabunNew":     "assets/THSarabunNew/THSarabunNew.ttf",
+	"THSarabunNewBold": "assets/THSarabunNew/THSarabunNew Bold.ttf",
+}
+
+var rendererImages = map[string]string{
+	"bg2":   "assets/image/bg2.png",
+	"logo":  "assets/image/logo.png",
+	"logo2": "assets/image/logo2.png",
+}
+
+// PDFRenderer สร้างเอกสาร PDF ในหน่วยความจำ โหลดฟอนต์และรูปภาพครั้งเดียวตอนเริ่มระบบ
+// ข้อมูลภายในอ่านอย่างเดียว จึงใช้พร้อมกันหลาย request ได้ (แต่ละเอกสารใช้ GoPdf ของตัวเอง)
+type PDFRenderer struct {
+	fonts       map[string][]byte
+	images      map[string][]byte
+	templates   map[string]*Template
+	templateDir string
+}
+
+// NewPDFRenderer templateDir คือโฟลเดอร์แม่แบบที่ใช้แทนแม่แบบเริ่มต้น (ว่างได้)
+func NewPDFRenderer(templateDir string) (*PDFRenderer, error) {
+	r := &PDFRenderer{
+		fonts:       map[string][]byte{},
+		images:      map[string][]byte{},
+		templates:   map[string]*Template{},
+		templateDir: templateDir,
+	}
+	for family, path := range rendererFonts {
+		data, err := assets.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load font %s: %w", family, err)
+		}
+		r.fonts[family] = data
+	}
+	for name, path := range rendererImages {
+		data, err := assets.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load image %s: %w", name, err)
+		}
+		if _, err := gopdf.ImageHolderByBytes(data); err != nil {
+			return nil, fmt.Errorf("invalid image %s: %w", name, err)
+		}
+		r.images[name] = data
+	}
+	if err := r.loadTemplates(); err != nil {
+		return nil, err
+	}
+	// ลองสร้างเอกสารเปล่าเพื่อให้ฟอนต์ที่เสียถูกพบตั้งแต่ตอนเริ่มระบบ
+	if _, err := r.newDocument(gopdf.Rect{W: 100, H: 100}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// newDocument สร้าง GoPdf ใหม่ที่ลงทะเบียนฟอนต์ไว้แล้ว
+func (r *PDFRenderer) newDocument(pageSize gopdf.Rect) (*gopdf.GoPdf, error) {
+	pdf := &gopdf.GoPdf{}
+	pdf.Start(gopdf.Config{PageSize: pageSize})
+	for family, data := range r.fonts {
+		if err := pdf.AddTTFFontData(family, data); err != nil {
+			return nil, fmt.Errorf("error adding font %s: %w", family, err)
+		}
+	}
+	return pdf, nil
+}
+
+// image วาดรูปที่โหลดไว้ลงในเอกสาร
+func (r *PDFRenderer) image(pdf *gopdf.GoPdf, name string, x float64, y float64, rect *gopdf.Rect) error {
+	data, ok := r.images[name]
+	if !ok {
+		return fmt.Errorf("image %s is not loaded", name)
+	}
+	holder, err := gopdf.ImageHolderByBytes(data)
+	if err != nil {
+		return fmt.Errorf("error loading image %s: %w", name, err)
+	}
+	if err := pdf.ImageByHolder(holder, x, y, rect); err != nil {
+		return fmt.Errorf("error drawing image %s: %w", name, err)
+	}
+	return nil
+}
+
+// output เขียนเอกสารลง memory buffer
+func output(pdf *gopdf.GoPdf) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := pdf.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("error writing PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -1,59 +1,27 @@
 package filesystem
 
 import (
-    "github.com/google/uuid"
-    "io"
-    "mime/multipart"
-    "os"
+    "RESTAPI/infrastructure/storage"
+    "bytes"
     "fmt"
-)
-
-func SaveFile(file *multipart.FileHeader, userID uint) (string, error) {
-    // เปิดไฟล์ที่อัปโหลด
-    srcFile, err := file.Open()
-    if err != nil {
-        return "", fmt.Errorf("failed to open file: %w", err)
-    }
-    defer srcFile.Close()
 
-    // กำหนดโฟลเดอร์อัปโหลด
-    uploadDir := "./uploads"
-    if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-        err = os.Mkdir(uploadDir, os.ModePerm)
-        if err != nil {
-            return "", fmt.Errorf("failed to create upload directory: %w", err)
-        }
-    }
+    "github.com/google/uuid"
+)
 
-    // โฟลเดอร์สำหรับผู้ใช้
-    userDir := fmt.Sprintf("%s/%d", uploadDir, userID)
-    if _, err := os.Stat(userDir); os.IsNotExist(err) {
-        err = os.Mkdir(userDir, os.ModePerm)
-        if err != nil {
-            return "", fmt.Errorf("failed to create user directory: %w", err)
-        }
-    }
+// SaveFile บันทึกไฟล์ที่ผ่านการตรวจแล้วลง storage และคืนค่า key ในรูปแบบ "<userID>/<uuid>.pdf"
+func SaveFile(store storage.Storage, data []byte, userID uint) (string, error) {
+    return SaveBytes(store, data, userID, "pdf", "application/pdf")
+}
 
+// SaveBytes บันทึกข้อมูลลง storage ด้วยชื่อไฟล์แบบ UUID และนามสกุลที่กำหนด
+func SaveBytes(store storage.Storage, data []byte, userID uint, ext string, contentType string) (string, error) {
     // สร้าง UUID สำหรับชื่อไฟล์
     uniqueID := uuid.New().String()
-    filePath := fmt.Sprintf("%s/%s.pdf", userDir, uniqueID)
+    key := fmt.Sprintf("%d/%s.%s", userID, uniqueID, ext)
 
-    // สร้างหรือเขียนทับไฟล์ใหม่
-    dstFile, err := os.Create(filePath)
-    if err != nil {
-        return "", fmt.Errorf("failed to create destination file: %w", err)
+    if err := store.Save(key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+        return "", err
     }
-    defer dstFile.Close()
 
-    // คัดลอกเนื้อหาไฟล์จากต้นทางไปยังปลายทาง
-    _, err = io.Copy(dstFile, srcFile)
-    if err != nil {
-        return "", fmt.Errorf("failed to copy file content: %w", err)
-    }
-
-    return filePath, nil
+    return key, nil
 }
-
-// func CreatePDF() error{
-    
-// }
@@ -0,0 +1,505 @@
+package filesystem
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/signintech/gopdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// แม่แบบเริ่มต้นฝังไว้ในโปรแกรม ถ้าตั้ง PDF_TEMPLATE_DIR ไฟล์ชื่อเดียวกันในโฟลเดอร์นั้นจะถูกใช้แทน
+//
+//go:embed assets/templates/*.json
+var defaultTemplates embed.FS
+
+// ประเภทขององค์ประกอบในแม่แบบ
+const (
+	ElementText       = "text"
+	ElementImage      = "image"
+	ElementRect       = "rect"
+	ElementSignature  = "signature"
+	ElementPageNumber = "page_number"
+	ElementQR         = "qr"
+)
+
+// Template เลย์เอาต์ของเอกสารหนึ่งชนิด อ่านจากไฟล์ JSON
+type Template struct {
+	Name       string     `json:"name"`
+	Version    string     `json:"version"`
+	FileName   string     `json:"file_name"`
+	Page       PageSpec   `json:"page"`
+	Elements   []Element  `json:"elements"`
+	Table      *TableSpec `json:"table"`
+	AfterTable *BlockSpec `json:"after_table"`
+}
+
+type PageSpec struct {
+	Width        float64 `json:"width"`
+	Height       float64 `json:"height"`
+	MarginBottom float64 `json:"margin_bottom"`
+}
+
+// Element องค์ประกอบที่วาดบนหน้า ข้อความใน Text, Label, Name และ Title ใช้รูปแบบ text/template เช่น {{.student_name}}
+type Element struct {
+	Type  string  `json:"type"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	W     float64 `json:"w"`
+	H     float64 `json:"h"`
+	Font  string  `json:"font"`
+	Size  float64 `json:"size"`
+	Align string  `json:"align"`
+	Text  string  `json:"text"`
+	Image string  `json:"image"`
+	Label string  `json:"label"`
+	Name  string  `json:"name"`
+	Title string  `json:"title"`
+	// HideSingle ใช้กับ page_number ไม่พิมพ์เลขหน้าถ้าเอกสารมีหน้าเดียว
+	HideSingle bool `json:"hide_single"`
+
+	text  *template.Template
+	label *template.Template
+	name  *template.Template
+	title *template.Template
+}
+
+// TableSpec ตารางที่ขึ้นหน้าใหม่อัตโนมัติเมื่อแถวเกิน RowsPerPage
+type TableSpec struct {
+	X           float64      `json:"x"`
+	Y           float64      `json:"y"`
+	RowHeight   float64      `json:"row_height"`
+	RowsPerPage int          `json:"rows_per_page"`
+	Font        string       `json:"font"`
+	Size        float64      `json:"size"`
+	Columns     []ColumnSpec `json:"columns"`
+}
+
+type ColumnSpec struct {
+	Header string  `json:"header"`
+	Width  float64 `json:"width"`
+	Align  string  `json:"align"`
+	Value  string  `json:"value"`
+
+	value *template.Template
+}
+
+// BlockSpec กลุ่มองค์ประกอบที่วางต่อท้ายตาราง (y ของแต่ละองค์ประกอบนับจากขอบบนของกลุ่ม)
+// ถ้าที่เหลือในหน้าน้อยกว่า MinHeight จะขึ้นหน้าใหม่และวางที่ Top
+type BlockSpec struct {
+	Gap       float64   `json:"gap"`
+	MinHeight float64   `json:"min_height"`
+	Top       float64   `json:"top"`
+	Elements  []Element `json:"elements"`
+}
+
+// Document ข้อมูลที่เติมลงในแม่แบบ
+type Document struct {
+	Fields map[string]string
+	Rows   []map[string]string
+}
+
+// Verification รหัสเอกสารที่ลงทะเบียนไว้ พิมพ์เป็น QR code ผ่านฟิลด์ verify_url และ document_code
+type Verification struct {
+	Code string
+	URL  string
+}
+
+func (v *Verification) apply(fields map[string]string) {
+	if v == nil {
+		return
+	}
+	fields["document_code"] = v.Code
+	fields["verify_url"] = v.URL
+}
+
+func parseText(name string, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	return template.New(name).Option("missingkey=zero").Parse(text)
+}
+
+func execText(tpl *template.Template, data map[string]string) (string, error) {
+	if tpl == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// compile ตรวจแม่แบบและเตรียม text/template ของทุกองค์ประกอบ
+func (t *Template) compile(r *PDFRenderer) error {
+	if t.Name == "" || t.Version == "" {
+		return fmt.Errorf("template must have a name and a version")
+	}
+	if t.Page.Width <= 0 || t.Page.Height <= 0 {
+		return fmt.Errorf("template %s has an invalid page size", t.Name)
+	}
+	if t.FileName == "" {
+		t.FileName = t.Name + ".pdf"
+	}
+	compileElements := func(elements []Element) error {
+		for i := range elements {
+			e := &elements[i]
+			var err error
+			if e.text, err = parseText(t.Name, e.Text); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			if e.label, err = parseText(t.Name, e.Label); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			if e.name, err = parseText(t.Name, e.Name); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			if e.title, err = parseText(t.Name, e.Title); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+			switch e.Type {
+			case ElementText, ElementRect, ElementSignature, ElementPageNumber, ElementQR:
+			case ElementImage:
+				if err := r.loadImage(e.Image); err != nil {
+					return fmt.Errorf("element %d: %w", i, err)
+				}
+			default:
+				return fmt.Errorf("element %d has unknown type %q", i, e.Type)
+			}
+			if e.Font != "" {
+				if _, ok := r.fonts[e.Font]; !ok {
+					return fmt.Errorf("element %d uses unknown font %q", i, e.Font)
+				}
+			}
+		}
+		return nil
+	}
+	if err := compileElements(t.Elements); err != nil {
+		return fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	if t.Table != nil {
+		if t.Table.RowHeight <= 0 || t.Table.RowsPerPage <= 0 || len(t.Table.Columns) == 0 {
+			return fmt.Errorf("template %s has an invalid table", t.Name)
+		}
+		for i := range t.Table.Columns {
+			var err error
+			if t.Table.Columns[i].value, err = parseText(t.Name, t.Table.Columns[i].Value); err != nil {
+				return fmt.Errorf("template %s column %d: %w", t.Name, i, err)
+			}
+		}
+	}
+	if t.AfterTable != nil {
+		if err := compileElements(t.AfterTable.Elements); err != nil {
+			return fmt.Errorf("template %s after_table: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadTemplates อ่านแม่แบบเริ่มต้นที่ฝังไว้ แล้วแทนที่ด้วยไฟล์ใน templateDir ถ้ามี
+// การแทนที่ใช้ชื่อไฟล์ ถ้าสองไฟล์ใช้ name เดียวกันจะไม่รู้ว่าควรใช้ไฟล์ใด จึงถือว่าแม่แบบไม่ถูกต้อง
+func (r *PDFRenderer) loadTemplates() error {
+	files := map[string][]byte{}
+	entries, err := defaultTemplates.ReadDir("assets/templates")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		data, err := defaultTemplates.ReadFile("assets/templates/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		files[entry.Name()] = data
+	}
+	if r.templateDir != "" {
+		matches, err := filepath.Glob(filepath.Join(r.templateDir, "*.json"))
+		if err != nil {
+			return err
+		}
+		for _, path := range matches {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read template %s: %w", path, err)
+			}
+			files[filepath.Base(path)] = data
+		}
+	}
+
+	sources := map[string]string{}
+	for file, data := range files {
+		var tpl Template
+		if err := json.Unmarshal(data, &tpl); err != nil {
+			return fmt.Errorf("invalid template %s: %w", file, err)
+		}
+		if err := tpl.compile(r); err != nil {
+			return err
+		}
+		if other, ok := sources[tpl.Name]; ok {
+			return fmt.Errorf("templates %s and %s both use the name %q", other, file, tpl.Name)
+		}
+		sources[tpl.Name] = file
+		r.templates[tpl.Name] = &tpl
+	}
+	return nil
+}
+
+// loadImage รูปที่ไม่ได้ฝังไว้ในโปรแกรมจะอ่านจาก templateDir
+func (r *PDFRenderer) loadImage(name string) error {
+	if _, ok := r.images[name]; ok {
+		return nil
+	}
+	if r.templateDir == "" {
+		return fmt.Errorf("image %q is not available", name)
+	}
+	path := filepath.Join(r.templateDir, filepath.Clean("/"+name))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("image %q is not available", name)
+		}
+		return err
+	}
+	if _, err := gopdf.ImageHolderByBytes(data); err != nil {
+		return fmt.Errorf("invalid image %q: %w", name, err)
+	}
+	r.images[name] = data
+	return nil
+}
+
+// Template คืนแม่แบบตามชื่อ
+func (r *PDFRenderer) Template(name string) (*Template, error) {
+	tpl, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %q not found", name)
+	}
+	return tpl, nil
+}
+
+// Render สร้างเอกสารจากแม่แบบ ถ้ามีตาราง แถวใน doc.Rows จะถูกแบ่งหน้าอัตโนมัติ
+func (r *PDFRenderer) Render(name string, doc Document) ([]byte, error) {
+	tpl, err := r.Template(name)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := gopdf.Rect{W: tpl.Page.Width, H: tpl.Page.Height}
+	pdf, err := r.newDocument(pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// แบ่งแถวเป็นหน้า ๆ ถ้าหน้าสุดท้ายเหลือที่ไม่พอสำหรับ after_table จะขึ้นหน้าใหม่
+	var pages [][]map[string]string
+	if tpl.Table != nil {
+		for rest := doc.Rows; len(rest) > 0; {
+			n := tpl.Table.RowsPerPage
+			if len(rest) < n {
+				n = len(rest)
+			}
+			pages = append(pages, rest[:n])
+			rest = rest[n:]
+		}
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+	blockTop := func(rows int) float64 {
+		if tpl.Table == nil || rows == 0 {
+			return tpl.AfterTable.Top
+		}
+		return tpl.Table.Y + float64(rows+1)*tpl.Table.RowHeight + tpl.AfterTable.Gap
+	}
+	blockOnNewPage := false
+	if tpl.AfterTable != nil {
+		top := blockTop(len(pages[len(pages)-1]))
+		blockOnNewPage = top+tpl.AfterTable.MinHeight > tpl.Page.Height-tpl.Page.MarginBottom
+	}
+	total := len(pages)
+	if blockOnNewPage {
+		total++
+	}
+
+	for i, rows := range pages {
+		pdf.AddPage()
+		if err := r.drawElements(pdf, tpl, tpl.Elements, 0, doc.Fields, i+1, total); err != nil {
+			return nil, err
+		}
+		if tpl.Table != nil && len(rows) > 0 {
+			if err := r.drawTable(pdf, tpl.Table, rows); err != nil {
+				return nil, fmt.Errorf("error drawing table: %w", err)
+			}
+		}
+		if i == len(pages)-1 && tpl.AfterTable != nil && !blockOnNewPage {
+			if err := r.drawElements(pdf, tpl, tpl.AfterTable.Elements, blockTop(len(rows)), doc.Fields, i+1, total); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if blockOnNewPage {
+		pdf.AddPage()
+		if err := r.drawElements(pdf, tpl, tpl.Elements, 0, doc.Fields, total, total); err != nil {
+			return nil, err
+		}
+		if err := r.drawElements(pdf, tpl, tpl.AfterTable.Elements, tpl.AfterTable.Top, doc.Fields, total, total); err != nil {
+			return nil, err
+		}
+	}
+	return output(pdf)
+}
+
+func (r *PDFRenderer) setFont(pdf *gopdf.GoPdf, font string, size float64) error {
+	if font == "" {
+		font = "THSarabunNew"
+	}
+	if size == 0 {
+		size = 16
+	}
+	return pdf.SetFont(font, "", size)
+}
+
+// drawElements วาดองค์ประกอบ โดยเลื่อนแนวตั้งด้วย offsetY
+func (r *PDFRenderer) drawElements(pdf *gopdf.GoPdf, tpl *Template, elements []Element, offsetY float64, fields map[string]string, page int, total int) error {
+	for _, e := range elements {
+		y := e.Y + offsetY
+		switch e.Type {
+		case ElementText:
+			text, err := execText(e.text, fields)
+			if err != nil {
+				return err
+			}
+			if err := r.setFont(pdf, e.Font, e.Size); err != nil {
+				return err
+			}
+			drawAligned(pdf, text, e.X, y, e.W, e.Align)
+		case ElementImage:
+			if err := r.image(pdf, e.Image, e.X, y, &gopdf.Rect{W: e.W, H: e.H}); err != nil {
+				return err
+			}
+		case ElementRect:
+			// ไม่ให้กรอบเกินขอบล่างของหน้า และไม่ให้ความสูงติดลบเมื่อกรอบเริ่มต่ำกว่าขอบล่าง
+			height := e.H
+			if y+height > tpl.Page.Height-tpl.Page.MarginBottom {
+				height = tpl.Page.Height - tpl.Page.MarginBottom - y
+			}
+			if height < 0 {
+				height = 0
+			}
+			pdf.SetStrokeColor(0, 0, 0)
+			pdf.SetLineWidth(0.5)
+			pdf.RectFromUpperLeftWithStyle(e.X, y, e.W, height, "D")
+			label, err := execText(e.label, fields)
+			if err != nil {
+				return err
+			}
+			if label != "" {
+				if err := r.setFont(pdf, e.Font, e.Size); err != nil {
+					return err
+				}
+				drawAligned(pdf, label, e.X, y+height/2-15, e.W, "center")
+			}
+		case ElementSignature:
+			name, err := execText(e.name, fields)
+			if err != nil {
+				return err
+			}
+			title, err := execText(e.title, fields)
+			if err != nil {
+				return err
+			}
+			if err := r.setFont(pdf, e.Font, e.Size); err != nil {
+				return err
+			}
+			line := "................................................................"
+			if name == "" {
+				name = "..................................................."
+			}
+			// ลายเซ็นกว้างเท่าเส้นประ ถ้ากำหนด align center จะวางกึ่งกลางของช่อง w
+			width, _ := pdf.MeasureTextWidth(line)
+			x := e.X
+			if e.Align == "center" && e.W > 0 {
+				x += (e.W - width) / 2
+			}
+			drawAligned(pdf, line, x, y, width, "left")
+			drawAligned(pdf, "(  "+name+"  )", x, y+30, width, "center")
+			drawAligned(pdf, title, x, y+60, width, "center")
+		case ElementQR:
+			// ไม่มีค่าให้เข้ารหัส (เช่น เอกสารที่ไม่ได้ลงทะเบียน) จะไม่วาด QR
+			content, err := execText(e.text, fields)
+			if err != nil {
+				return err
+			}
+			if content == "" {
+				continue
+			}
+			png, err := qrcode.Encode(content, qrcode.Medium, 256)
+			if err != nil {
+				return fmt.Errorf("error encoding QR code: %w", err)
+			}
+			holder, err := gopdf.ImageHolderByBytes(png)
+			if err != nil {
+				return err
+			}
+			if err := pdf.ImageByHolder(holder, e.X, y, &gopdf.Rect{W: e.W, H: e.W}); err != nil {
+				return fmt.Errorf("error drawing QR code: %w", err)
+			}
+		case ElementPageNumber:
+			if e.HideSingle && total <= 1 {
+				continue
+			}
+			if err := r.setFont(pdf, e.Font, e.Size); err != nil {
+				return err
+			}
+			drawAligned(pdf, fmt.Sprintf("หน้า %d/%d", page, total), e.X, y, e.W, e.Align)
+		}
+	}
+	return nil
+}
+
+// drawAligned วางข้อความชิดซ้าย กึ่งกลาง หรือชิดขวาภายในความกว้าง width ที่เริ่มจาก x
+func drawAligned(pdf *gopdf.GoPdf, text string, x float64, y float64, width float64, align string) {
+	if text == "" {
+		return
+	}
+	if align == "center" || align == "right" {
+		textWidth, err := pdf.MeasureTextWidth(text)
+		if err == nil {
+			if align == "center" {
+				x += (width - textWidth) / 2
+			} else {
+				x += width - textWidth
+			}
+		}
+	}
+	pdf.SetXY(x, y)
+	pdf.Cell(nil, text)
+}
+
+func (r *PDFRenderer) drawTable(pdf *gopdf.GoPdf, spec *TableSpec, rows []map[string]string) error {
+	if err := r.setFont(pdf, spec.Font, spec.Size); err != nil {
+		return err
+	}
+	table := pdf.NewTableLayout(spec.X, spec.Y, spec.RowHeight, len(rows))
+	for _, col := range spec.Columns {
+		table.AddColumn(col.Header, col.Width, col.Align)
+	}
+	for _, row := range rows {
+		cells := make([]string, 0, len(spec.Columns))
+		for _, col := range spec.Columns {
+			value, err := execText(col.value, row)
+			if err != nil {
+				return err
+			}
+			cells = append(cells, fitText(pdf, strings.TrimSpace(value), col.Width-6))
+		}
+		table.AddRow(cells)
+	}
+	return table.DrawTable()
+}
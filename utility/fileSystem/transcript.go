@@ -0,0 +1,114 @@
+package filesystem
+
+import (
+	"RESTAPI/utility"
+	"fmt"
+	"time"
+)
+
+// TemplateTranscript ชื่อแม่แบบของใบสรุปชั่วโมงกิจกรรม
+const TemplateTranscript = "transcript"
+
+// TranscriptActivity กิจกรรมหนึ่งแถวในใบสรุป
+type TranscriptActivity struct {
+	Name   string
+	Kind   string
+	Date   string
+	Hours  uint
+	Status string
+}
+
+// TranscriptYear กิจกรรมและชั่วโมงรวมของปีการศึกษาหนึ่ง
+type TranscriptYear struct {
+	SchoolYear   uint
+	Activities   []TranscriptActivity
+	EarnedHours  uint
+	PendingHours uint
+}
+
+// TranscriptCategory ชั่วโมงรวมของหมวดหมู่กิจกรรมหนึ่งตลอดช่วงเวลาของใบสรุป
+type TranscriptCategory struct {
+	Name         string
+	EarnedHours  uint
+	PendingHours uint
+}
+
+// TranscriptData ข้อมูลใบสรุปชั่วโมงกิจกรรมของนักศึกษา RequiredHours เป็น 0 ถ้าไม่กำหนด
+// Result คือผลการประเมินตามเกณฑ์ของนักศึกษา ว่างถ้าไม่มีเกณฑ์
+type TranscriptData struct {
+	StudentName   string
+	StudentCode   string
+	BranchName    string
+	FacultyName   string
+	Period        string
+	Years         []TranscriptYear
+	Categories    []TranscriptCategory
+	EarnedHours   uint
+	PendingHours  uint
+	RequiredHours uint
+	Result        string
+	IssuedAt      time.Time
+	Verification  *Verification
+}
+
+// Transcript สร้างใบสรุปชั่วโมงกิจกรรมจากแม่แบบ transcript โดยมีแถวสรุปท้ายแต่ละปีการศึกษา
+// และแถวสรุปชั่วโมงตามหมวดหมู่ต่อท้ายตาราง
+func (r *PDFRenderer) Transcript(data TranscriptData) ([]byte, error) {
+	required := "ไม่กำหนด"
+	result := "ไม่กำหนดจำนวนชั่วโมงขั้นต่ำ"
+	if data.RequiredHours > 0 {
+		required = fmt.Sprintf("%d ชั่วโมง", data.RequiredHours)
+	}
+	if data.Result != "" {
+		result = data.Result
+	}
+	doc := Document{
+		Fields: map[string]string{
+			"student_name":   data.StudentName,
+			"student_code":   data.StudentCode,
+			"branch":         data.BranchName,
+			"faculty":        data.FacultyName,
+			"period":         data.Period,
+			"earned_hours":   fmt.Sprint(data.EarnedHours),
+			"pending_hours":  fmt.Sprint(data.PendingHours),
+			"required_hours": required,
+			"result":         result,
+			"issued_date":    utility.FormatToThaiDate(data.IssuedAt),
+		},
+	}
+	data.Verification.apply(doc.Fields)
+	for _, year := range data.Years {
+		for _, activity := range year.Activities {
+			doc.Rows = append(doc.Rows, map[string]string{
+				"year":   fmt.Sprint(year.SchoolYear),
+				"name":   activity.Name,
+				"kind":   activity.Kind,
+				"date":   activity.Date,
+				"hours":  fmt.Sprint(activity.Hours),
+				"status": activity.Status,
+			})
+		}
+		summary := map[string]string{
+			"name":  fmt.Sprintf("รวมปีการศึกษา %d", year.SchoolYear),
+			"hours": fmt.Sprint(year.EarnedHours),
+		}
+		if year.PendingHours > 0 {
+			summary["status"] = fmt.Sprintf("รอรับรอง %d ชม.", year.PendingHours)
+		}
+		doc.Rows = append(doc.Rows, summary)
+	}
+	if len(data.Categories) > 0 {
+		doc.Rows = append(doc.Rows, map[string]string{"name": "สรุปชั่วโมงตามหมวดหมู่"})
+		for _, category := range data.Categories {
+			row := map[string]string{
+				"name":  category.Name,
+				"hours": fmt.Sprint(category.EarnedHours),
+			}
+			if category.PendingHours > 0 {
+				row["status"] = fmt.Sprintf("รอรับรอง %d ชม.", category.PendingHours)
+			}
+			doc.Rows = append(doc.Rows, row)
+		}
+	}
+	return r.Render(TemplateTranscript, doc)
+}
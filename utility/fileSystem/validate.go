@@ -0,0 +1,353 @@
+package filesystem
+
+import (
+	"RESTAPI/infrastructure/scanner"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// รหัสข้อผิดพลาดของการตรวจไฟล์ ส่งกลับไปใน response ให้ frontend แสดงข้อความได้ถูกต้อง
+const (
+	CodeFileTooLarge    = "file_too_large"
+	CodeNotPDF          = "not_pdf"
+	CodeInvalidPDF      = "invalid_pdf"
+	CodeTooManyPages    = "too_many_pages"
+	CodePDFJavaScript   = "pdf_javascript"
+	CodePDFLaunchAction = "pdf_launch_action"
+	CodeMalwareDetected = "malware_detected"
+	CodeScanFailed      = "scan_failed"
+	CodeAttachmentLimit = "attachment_limit"
+)
+
+// ValidationError ไฟล์ไม่ผ่านการตรวจ พร้อมรหัสข้อผิดพลาด
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(code string, format string, args ...interface{}) error {
+	return &ValidationError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// PDFValidator ตรวจเนื้อหาไฟล์ PDF จริง ไม่เชื่อ Content-Type ที่ client ส่งมา
+type PDFValidator struct {
+	MaxSize  int64
+	MaxPages int
+	Scanner  scanner.Scanner
+}
+
+func NewPDFValidator(maxSize int64, maxPages int, s scanner.Scanner) *PDFValidator {
+	if s == nil {
+		s = scanner.NewNoopScanner()
+	}
+	return &PDFValidator{MaxSize: maxSize, MaxPages: maxPages, Scanner: s}
+}
+
+var (
+	pdfHeader    = []byte("%PDF-")
+	pdfEOF       = []byte("%%EOF")
+	startxrefRe  = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF`)
+	xrefObjRe    = regexp.MustCompile(`^\s*\d+\s+\d+\s+obj`)
+	streamRe     = regexp.MustCompile(`stream\r?\n`)
+	pdfNameRe    = regexp.MustCompile(`/[^\s/<>\[\]()%{}]+`)
+	nameEscapeRe = regexp.MustCompile(`#([0-9A-Fa-f]{2})`)
+	objRe        = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	refRe        = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	rootRe       = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R\b`)
+	pagesRe      = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R\b`)
+	kidsRe       = regexp.MustCompile(`/Kids\s*(\[[^\]]*\]|\d+\s+\d+\s+R\b)`)
+	objStmRe     = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	firstRe      = regexp.MustCompile(`/First\s+(\d+)`)
+)
+
+// maxPageTreeNodes จำกัดจำนวน node ที่เดินใน page tree กันไฟล์ที่สร้าง tree ขนาดใหญ่ผิดปกติ
+const maxPageTreeNodes = 100000
+
+// maxInflatedObjects ขนาดรวมสูงสุดของ object stream หลังคลายการบีบอัด (กัน zip bomb)
+// ไฟล์ที่ต้องคลายเกินนี้ถือว่าไม่ถูกต้อง ไม่ตัดทิ้งแล้วตรวจเพียงบางส่วน
+const maxInflatedObjects = 8 << 20
+
+// ReadAndValidate อ่านไฟล์ไม่เกิน MaxSize แล้วตรวจ คืนข้อมูลไฟล์ที่ผ่านการตรวจแล้ว
+func (v *PDFValidator) ReadAndValidate(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, v.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := v.Validate(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Validate ตรวจขนาด, magic bytes, โครงสร้าง xref, จำนวนหน้า, JavaScript/Launch action และมัลแวร์
+func (v *PDFValidator) Validate(data []byte) error {
+	if int64(len(data)) > v.MaxSize {
+		return invalid(CodeFileTooLarge, "file exceeds the maximum size of %d bytes", v.MaxSize)
+	}
+
+	// ตาม spec header อยู่ภายใน 1024 ไบต์แรก
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if !bytes.Contains(head, pdfHeader) {
+		return invalid(CodeNotPDF, "only PDF files are allowed")
+	}
+
+	xrefOffset, err := checkStructure(data)
+	if err != nil {
+		return err
+	}
+
+	doc, err := parsePDF(data)
+	if err != nil {
+		return err
+	}
+
+	// ตรวจเฉพาะชื่อใน dictionary ของ object (รวม object ใน object stream)
+	// ไม่ตรวจเนื้อหาของ stream อย่างรูปภาพหรือฟอนต์ ซึ่งอาจมีไบต์ที่บังเอิญเหมือน /JS
+	for _, dict := range doc.dicts {
+		for _, name := range pdfNameRe.FindAll(dict, -1) {
+			switch string(name[1:]) {
+			case "JavaScript", "JS":
+				return invalid(CodePDFJavaScript, "PDF files containing JavaScript are not allowed")
+			case "Launch":
+				return invalid(CodePDFLaunchAction, "PDF files containing launch actions are not allowed")
+			}
+		}
+	}
+
+	pages, err := countPages(data, doc.objects, xrefOffset)
+	if err != nil {
+		return err
+	}
+	if pages == 0 {
+		return invalid(CodeInvalidPDF, "PDF file has no pages")
+	}
+	if v.MaxPages > 0 && pages > v.MaxPages {
+		return invalid(CodeTooManyPages, "PDF file has %d pages, the maximum is %d", pages, v.MaxPages)
+	}
+
+	return v.Scan(data)
+}
+
+// Scan ส่งไฟล์ให้ scanner ตรวจมัลแวร์ ใช้กับไฟล์ทุกประเภท
+func (v *PDFValidator) Scan(data []byte) error {
+	if err := v.Scanner.Scan(data); err != nil {
+		if errors.Is(err, scanner.ErrInfected) {
+			return invalid(CodeMalwareDetected, "file was rejected by the malware scanner")
+		}
+		return invalid(CodeScanFailed, "failed to scan file: %v", err)
+	}
+	return nil
+}
+
+// checkStructure ต้องมี %%EOF และ startxref ที่ชี้ไปยังตาราง xref หรือ xref stream ภายในไฟล์
+// คืนตำแหน่งของ xref ชุดสุดท้าย ซึ่งเป็นของ incremental update ล่าสุด
+func checkStructure(data []byte) (int, error) {
+	tail := data
+	if len(tail) > 2048 {
+		tail = tail[len(tail)-2048:]
+	}
+	if !bytes.Contains(tail, pdfEOF) {
+		return 0, invalid(CodeInvalidPDF, "PDF file is truncated (missing %%EOF)")
+	}
+	matches := startxrefRe.FindAllSubmatch(tail, -1)
+	if len(matches) == 0 {
+		return 0, invalid(CodeInvalidPDF, "PDF file has no startxref")
+	}
+	offset, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if err != nil || offset <= 0 || offset >= len(data) {
+		return 0, invalid(CodeInvalidPDF, "PDF file has an invalid xref offset")
+	}
+	at := data[offset:]
+	if len(at) > 64 {
+		at = at[:64]
+	}
+	if !bytes.HasPrefix(bytes.TrimLeft(at, " \r\n\t"), []byte("xref")) && !xrefObjRe.Match(at) {
+		return 0, invalid(CodeInvalidPDF, "PDF file has a broken cross-reference table")
+	}
+	return offset, nil
+}
+
+// countPages นับหน้าจาก page tree ของ /Root ใน trailer ของ xref ชุดสุดท้าย
+// ไม่นับ /Type /Page ทั้งไฟล์ เพราะ incremental update อาจแทนที่ page tree เดิมด้วย tree ที่มีหน้ามากกว่า
+// object ที่ถูกนิยามซ้ำใช้ตัวที่อยู่หลังสุดในไฟล์ รวมถึง object ใน object stream
+func countPages(data []byte, objects map[string][]byte, xrefOffset int) (int, error) {
+	root := rootRe.FindSubmatch(decodeNames(trailerAt(data, xrefOffset)))
+	if root == nil {
+		return 0, invalid(CodeInvalidPDF, "PDF file has no document catalog")
+	}
+	catalog, ok := objects[string(root[1])]
+	if !ok {
+		return 0, invalid(CodeInvalidPDF, "PDF file has no document catalog")
+	}
+	tree := pagesRe.FindSubmatch(catalog)
+	if tree == nil {
+		return 0, invalid(CodeInvalidPDF, "PDF file has no page tree")
+	}
+
+	// หน้าที่ถูกอ้างถึงซ้ำนับทุกครั้งเหมือนที่ viewer แสดง ส่วน node ที่มี /Kids เดินเพียงครั้งเดียวกัน tree ที่วนกลับ
+	pages := 0
+	walked := 0
+	visited := map[string]bool{}
+	queue := []string{string(tree[1])}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if walked++; walked > maxPageTreeNodes {
+			return 0, invalid(CodeInvalidPDF, "PDF file has an invalid page tree")
+		}
+		node, ok := objects[id]
+		if !ok {
+			continue
+		}
+		kids := kidsRe.FindSubmatch(node)
+		if kids == nil {
+			pages++
+			continue
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		list := kids[1]
+		if match := refRe.FindSubmatch(list); match != nil && list[0] != '[' {
+			list = objects[string(match[1])]
+		}
+		for _, ref := range refRe.FindAllSubmatch(list, -1) {
+			queue = append(queue, string(ref[1]))
+		}
+	}
+	return pages, nil
+}
+
+// trailerAt dictionary ของ trailer ที่ตำแหน่ง xref ตารางแบบเดิมใช้ส่วนหลังคำว่า trailer ส่วน xref stream ใช้ dictionary ของ object นั้น
+func trailerAt(data []byte, offset int) []byte {
+	section := data[offset:]
+	if end := bytes.Index(section, []byte("startxref")); end >= 0 {
+		section = section[:end]
+	}
+	if start := bytes.Index(section, []byte("trailer")); start >= 0 {
+		return section[start:]
+	}
+	if end := bytes.Index(section, []byte("stream")); end >= 0 {
+		section = section[:end]
+	}
+	return section
+}
+
+// pdfDocument object ที่อ่านได้จากไฟล์ dictionary ถอดรหัส #xx ในชื่อแล้วและตัดส่วน stream ออก
+type pdfDocument struct {
+	// objects dictionary ล่าสุดของแต่ละหมายเลข object ที่นิยามภายหลังแทนที่ตัวก่อนหน้า
+	// object ใน object stream ถือว่านิยามที่ตำแหน่งของ stream นั้น
+	objects map[string][]byte
+	// dicts ทุก dictionary ที่พบ รวมตัวที่ถูกแทนที่แล้ว ใช้ตรวจ JavaScript
+	dicts [][]byte
+}
+
+// parsePDF อ่าน object ทั้งหมด คลายเฉพาะ object stream ครั้งละหนึ่ง stream โดยใช้งบ maxInflatedObjects ร่วมกัน
+func parsePDF(data []byte) (*pdfDocument, error) {
+	doc := &pdfDocument{objects: map[string][]byte{}}
+	budget := int64(maxInflatedObjects)
+	matches := objRe.FindAllSubmatchIndex(data, -1)
+	for i, loc := range matches {
+		end := len(data)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := data[loc[1]:end]
+		if e := bytes.Index(body, []byte("endobj")); e >= 0 {
+			body = body[:e]
+		}
+		dict := body
+		var stream []byte
+		if s := streamRe.FindIndex(body); s != nil {
+			dict = body[:s[0]]
+			stream = body[s[1]:]
+			if e := bytes.Index(stream, []byte("endstream")); e >= 0 {
+				stream = stream[:e]
+			}
+		}
+		dict = decodeNames(dict)
+		doc.objects[string(data[loc[2]:loc[3]])] = dict
+		doc.dicts = append(doc.dicts, dict)
+
+		if stream == nil || !objStmRe.Match(dict) {
+			continue
+		}
+		content, err := inflate(stream, &budget)
+		if err != nil {
+			return nil, err
+		}
+		for id, member := range objectStream(dict, content) {
+			member = decodeNames(member)
+			doc.objects[id] = member
+			doc.dicts = append(doc.dicts, member)
+		}
+	}
+	return doc, nil
+}
+
+// objectStream แยก object ใน object stream ส่วนหัวคือคู่ "หมายเลข offset" จำนวน /N คู่ ข้อมูลเริ่มที่ /First
+func objectStream(dict []byte, content []byte) map[string][]byte {
+	first := firstRe.FindSubmatch(dict)
+	if first == nil {
+		return nil
+	}
+	start, err := strconv.Atoi(string(first[1]))
+	if err != nil || start <= 0 || start > len(content) {
+		return nil
+	}
+	fields := bytes.Fields(content[:start])
+	objects := map[string][]byte{}
+	for i := 0; i+1 < len(fields); i += 2 {
+		offset, err := strconv.Atoi(string(fields[i+1]))
+		if err != nil || start+offset > len(content) {
+			return objects
+		}
+		end := len(content)
+		if i+3 < len(fields) {
+			if next, err := strconv.Atoi(string(fields[i+3])); err == nil && start+next >= start+offset && start+next <= len(content) {
+				end = start + next
+			}
+		}
+		objects[string(fields[i])] = content[start+offset : end]
+	}
+	return objects
+}
+
+// decodeNames ถอดรหัส #xx ในชื่อ เพื่อไม่ให้ซ่อน /Kids หรือ /Root ด้วยการเข้ารหัสชื่อได้
+func decodeNames(content []byte) []byte {
+	return pdfNameRe.ReplaceAllFunc(content, func(name []byte) []byte {
+		return nameEscapeRe.ReplaceAllFunc(name, func(m []byte) []byte {
+			b, _ := strconv.ParseUint(string(m[1:]), 16, 8)
+			return []byte{byte(b)}
+		})
+	})
+}
+
+// inflate คลาย stream ที่บีบอัดแบบ Flate แล้วหักขนาดออกจาก budget ถ้าเกิน budget ถือว่าไฟล์ไม่ถูกต้อง
+// stream ที่คลายไม่ได้คืน nil เหมือน object stream ที่ไม่มี object
+func inflate(stream []byte, budget *int64) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return nil, nil
+	}
+	defer zr.Close()
+	content, _ := io.ReadAll(io.LimitReader(zr, *budget+1))
+	if int64(len(content)) > *budget {
+		return nil, invalid(CodeInvalidPDF, "PDF file has too much compressed object data")
+	}
+	*budget -= int64(len(content))
+	return content, nil
+}
@@ -0,0 +1,155 @@
+package filesystem
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// buildPDF สร้างไฟล์ PDF จาก object ตามลำดับ (object แรกคือหมายเลข 1) โดยมี Catalog เป็น object 1
+// แต่ละ update คือ incremental update ที่ต่อท้ายไฟล์ พร้อม xref และ trailer ของตัวเอง
+// object แรกของ update แทนที่ Catalog ส่วน object ที่เหลือได้หมายเลขต่อจากเดิม
+func buildPDF(objects []string, updates ...[]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	next, prev := 1, 0
+	for i, section := range append([][]string{objects}, updates...) {
+		ids := make([]int, len(section))
+		for j := range section {
+			if i > 0 && j == 0 {
+				ids[j] = 1
+				continue
+			}
+			ids[j] = next
+			next++
+		}
+		offsets := make([]int, 0, len(section))
+		for j, object := range section {
+			offsets = append(offsets, buf.Len())
+			fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", ids[j], object)
+		}
+		xref := buf.Len()
+		buf.WriteString("xref\n0 1\n0000000000 65535 f \n")
+		for j, offset := range offsets {
+			fmt.Fprintf(&buf, "%d 1\n%010d 00000 n \n", ids[j], offset)
+		}
+		fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R", next)
+		if prev > 0 {
+			fmt.Fprintf(&buf, " /Prev %d", prev)
+		}
+		fmt.Fprintf(&buf, " >>\nstartxref\n%d\n%%%%EOF\n", xref)
+		prev = xref
+	}
+	return buf.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func pdfStream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+// objectStreamOf object stream ที่เก็บ object หมายเลข ids ตามลำดับ
+func objectStreamOf(ids []int, members []string) string {
+	var header, body bytes.Buffer
+	for i, member := range members {
+		fmt.Fprintf(&header, "%d %d ", ids[i], body.Len())
+		body.WriteString(member + " ")
+	}
+	content := append(header.Bytes(), body.Bytes()...)
+	return pdfStream(fmt.Sprintf("/Type /ObjStm /N %d /First %d /Filter /FlateDecode", len(members), header.Len()), deflate(content))
+}
+
+func validationCode(err error) string {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Code
+	}
+	return ""
+}
+
+var onePage = []string{
+	"<< /Type /Catalog /Pages 2 0 R >>",
+	"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+	"<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im1 4 0 R >> >> >>",
+}
+
+func TestValidateIgnoresNamesInsideStreamData(t *testing.T) {
+	// รูปภาพที่เป็นข้อมูลสุ่ม และมีไบต์ที่บังเอิญเป็น /JS และ /Launch อยู่ในเนื้อหา
+	data := make([]byte, 256<<10)
+	rand.New(rand.NewSource(1)).Read(data)
+	copy(data[1000:], "/JS ")
+	copy(data[5000:], "/Launch ")
+	image := pdfStream("/Type /XObject /Subtype /Image /Width 512 /Height 512 /BitsPerComponent 8 /ColorSpace /DeviceGray /Filter /DCTDecode", data)
+
+	v := NewPDFValidator(10<<20, 10, nil)
+	if err := v.Validate(buildPDF(append(onePage, image))); err != nil {
+		t.Fatalf("expected image stream with random data to pass, got %v", err)
+	}
+}
+
+func TestValidateRejectsJavaScript(t *testing.T) {
+	v := NewPDFValidator(10<<20, 10, nil)
+	catalog := "<< /Type /Catalog /Pages 2 0 R /OpenAction 4 0 R >>"
+	tests := map[string][]byte{
+		"dictionary":    buildPDF([]string{catalog, onePage[1], onePage[2], "<< /S /JavaScript /JS (app.alert(1)) >>"}),
+		"escaped name":  buildPDF([]string{catalog, onePage[1], onePage[2], "<< /S /J#61vaScript /J#53 (app.alert(1)) >>"}),
+		"object stream": buildPDF([]string{catalog, onePage[1], onePage[2], objectStreamOf([]int{5}, []string{"<< /S /JavaScript /JS (app.alert(1)) >>"})}),
+		"launch action": buildPDF([]string{catalog, onePage[1], onePage[2], "<< /S /Launch /F (cmd.exe) >>"}),
+	}
+	for name, pdf := range tests {
+		code := validationCode(v.Validate(pdf))
+		if code != CodePDFJavaScript && code != CodePDFLaunchAction {
+			t.Errorf("%s: expected the file to be rejected, got %q", name, code)
+		}
+	}
+}
+
+func TestValidateCountsPagesOfFinalPageTree(t *testing.T) {
+	v := NewPDFValidator(10<<20, 2, nil)
+	if err := v.Validate(buildPDF(onePage)); err != nil {
+		t.Fatalf("expected one page file to pass, got %v", err)
+	}
+
+	// incremental update แทนที่ page tree ด้วย tree ที่มี 4 หน้า
+	update := []string{
+		"<< /Type /Catalog /P#61ges 4 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 5 0 R 6 0 R 7 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 4 0 R >>",
+		"<< /Type /Page /Parent 4 0 R >>",
+		"<< /Type /Page /Parent 4 0 R >>",
+	}
+	if code := validationCode(v.Validate(buildPDF(onePage, update))); code != CodeTooManyPages {
+		t.Errorf("incremental update: expected %q, got %q", CodeTooManyPages, code)
+	}
+
+	// page tree ใหม่อยู่ใน object stream
+	packed := []string{
+		"<< /Type /Catalog /Pages 5 0 R >>",
+		objectStreamOf([]int{5, 6, 7}, []string{
+			"<< /Type /Pages /Kids [3 0 R 6 0 R 7 0 R] >>",
+			"<< /Type /Page /Parent 5 0 R >>",
+			"<< /Type /Page /Parent 5 0 R >>",
+		}),
+	}
+	if code := validationCode(v.Validate(buildPDF(onePage, packed))); code != CodeTooManyPages {
+		t.Errorf("object stream: expected %q, got %q", CodeTooManyPages, code)
+	}
+}
+
+func TestValidateRejectsObjectStreamBomb(t *testing.T) {
+	bomb := pdfStream("/Type /ObjStm /N 1 /First 4 /Filter /FlateDecode", deflate(make([]byte, maxInflatedObjects+1)))
+	v := NewPDFValidator(10<<20, 10, nil)
+	if code := validationCode(v.Validate(buildPDF(append(onePage, bomb)))); code != CodeInvalidPDF {
+		t.Errorf("expected %q, got %q", CodeInvalidPDF, code)
+	}
+}
@@ -49,6 +49,32 @@ func FormatToThaiDate(t time.Time) string {
     return fmt.Sprintf("%02d %s %d", day, month, year)
 }
 
+var thaiShortMonths = []string{
+    "ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.",
+    "ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค.",
+}
+
+// FormatToThaiShortDate แปลงวันที่เป็นรูปแบบย่อ เช่น "9 ม.ค. 2568"
+func FormatToThaiShortDate(t time.Time) string {
+    location, err := time.LoadLocation("Asia/Bangkok")
+    if err != nil {
+        return "Failed to load location"
+    }
+    t = t.In(location)
+    return fmt.Sprintf("%d %s %d", t.Day(), thaiShortMonths[t.Month()-1], t.Year()+543)
+}
+
+// FormatToThaiTimeRange ช่วงเวลาเริ่มต้นถึงเวลาสิ้นสุดตามจำนวนชั่วโมง เช่น "10:00-16:00"
+func FormatToThaiTimeRange(start time.Time, hours uint) string {
+    location, err := time.LoadLocation("Asia/Bangkok")
+    if err != nil {
+        return "Failed to load location"
+    }
+    start = start.In(location)
+    end := start.Add(time.Duration(hours) * time.Hour)
+    return start.Format("15:04") + "-" + end.Format("15:04")
+}
+
 // ฟังก์ชันแปลง time.Time เป็นรูปแบบเวลา
 func FormatToThaiTime(t time.Time) string {
     // ตั้งค่า Location เป็น "Asia/Bangkok"
@@ -114,3 +140,25 @@ func DecodeIDs(dataStr string) ([]uint, error) {
 
 	return ids, nil
 }
+
+// DecodeStrings แปลง JSON array ของข้อความ (เช่นแท็กของกิจกรรม) ค่าว่างคือไม่มีข้อมูล
+func DecodeStrings(dataStr string) ([]string, error) {
+	var values []string
+	if dataStr == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(dataStr), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ContainsUint ตรวจสอบว่ามี id อยู่ใน ids หรือไม่
+func ContainsUint(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxRows จำนวนแถวข้อมูลสูงสุดต่อไฟล์นำเข้าหนึ่งไฟล์
+const MaxRows = 5000
+
+// Row ข้อมูลหนึ่งแถวของไฟล์นำเข้า Line คือเลขบรรทัดในไฟล์ (นับหัวตารางเป็นบรรทัดที่ 1)
+type Row struct {
+	Line   int
+	Values map[string]string
+}
+
+// Get ค่าของคอลัมน์ key (ชื่อหัวตารางตัวพิมพ์เล็ก) ตัดช่องว่างหัวท้ายแล้ว
+func (r Row) Get(key string) string {
+	return r.Values[key]
+}
+
+// ReadRows อ่านไฟล์ CSV หรือ XLSX (ตามนามสกุลของ fileName) แถวแรกเป็นหัวตาราง ข้ามแถวว่าง
+// XLSX อ่านเฉพาะแผ่นงานแรก
+func ReadRows(fileName string, src io.Reader) ([]Row, error) {
+	var records [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		records, err = readCSV(src)
+	case ".xlsx":
+		records, err = readXLSX(src)
+	default:
+		return nil, fmt.Errorf("unsupported file type, use .csv or .xlsx")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, name := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+	var rows []Row
+	for i, record := range records[1:] {
+		row := Row{Line: i + 2, Values: map[string]string{}}
+		for _, name := range header {
+			if name != "" {
+				row.Values[name] = ""
+			}
+		}
+		blank := true
+		for j, value := range record {
+			if j >= len(header) || header[j] == "" {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if value != "" {
+				blank = false
+			}
+			row.Values[header[j]] = value
+		}
+		if blank {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", MaxRows)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("file has no data rows")
+	}
+	return rows, nil
+}
+
+// Require ตรวจว่าหัวตารางมีคอลัมน์ที่จำเป็นครบ (ทุกแถวมีทุกคอลัมน์ของหัวตาราง แม้ช่องจะว่าง)
+func Require(rows []Row, columns ...string) error {
+	var missing []string
+	for _, column := range columns {
+		if _, ok := rows[0].Values[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func readCSV(src io.Reader) ([][]string, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %w", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func readXLSX(src io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	defer file.Close()
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("XLSX file has no sheets")
+	}
+	records, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	return records, nil
+}